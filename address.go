@@ -0,0 +1,161 @@
+package digicert
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Address represents the postal address of organizations and ship info, fields left empty are not sent.
+type Address struct {
+	Line1   string `json:"address,omitempty"`
+	Line2   string `json:"address2,omitempty"`
+	City    string `json:"city,omitempty"`
+	State   string `json:"state,omitempty"`
+	Zip     string `json:"zip,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// ShipInfo represents where DigiCert ships the hardware token of code and document signing orders
+type ShipInfo struct {
+	Name string
+	Address
+	Method string
+}
+
+// shipInfoJSON is the wire form of ShipInfo, the ship_info object names the address lines addr1 and addr2.
+type shipInfoJSON struct {
+	Name    string `json:"name"`
+	Addr1   string `json:"addr1"`
+	Addr2   string `json:"addr2"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Zip     string `json:"zip"`
+	Country string `json:"country"`
+	Method  string `json:"method"`
+}
+
+// MarshalJSON encodes the ship info with the field names of the ship_info object.
+func (s ShipInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(shipInfoJSON{s.Name, s.Line1, s.Line2, s.City, s.State, s.Zip, s.Country, s.Method})
+}
+
+// UnmarshalJSON decodes a ship_info object.
+func (s *ShipInfo) UnmarshalJSON(data []byte) error {
+	var v shipInfoJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = ShipInfo{Name: v.Name, Address: Address{v.Addr1, v.Addr2, v.City, v.State, v.Zip, v.Country}, Method: v.Method}
+	return nil
+}
+
+// ValidateCountry exports checks code is an ISO 3166-1 alpha-2 country code, in any case.
+func ValidateCountry(code string) error {
+	if !countryCodes[strings.ToLower(code)] {
+		return errors.New("The country must be an ISO 3166-1 alpha-2 code: " + code)
+	}
+	return nil
+}
+
+// ValidateTelephone exports checks number looks like an E.164 number: an optional leading +, 7 to 15 digits and the usual spaces, dots, dashes and parentheses, e.g. "+44 20 7946 0000".
+func ValidateTelephone(number string) error {
+	digits := 0
+	for i, r := range strings.TrimSpace(number) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case r == ' ' || r == '.' || r == '-' || r == '(' || r == ')':
+		default:
+			return errors.New("The telephone contains an unexpected character: " + number)
+		}
+	}
+	if digits < 7 || digits > 15 {
+		return errors.New("The telephone must have 7 to 15 digits: " + number)
+	}
+	return nil
+}
+
+// Validate checks the country and telephones of the organization and its contact.
+func (r *NewOrganizationRequest) Validate() error {
+	if err := r.Address.Validate(); err != nil {
+		return err
+	}
+	if err := ValidateTelephone(r.Telephone); err != nil {
+		return err
+	}
+	return r.OrganizationContact.Validate()
+}
+
+// Validate checks the fields being updated, empty fields are left untouched by the API.
+func (r *UpdateOrganizationRequest) Validate() error {
+	if r.Country != "" {
+		if err := ValidateCountry(r.Country); err != nil {
+			return err
+		}
+	}
+	if r.Telephone != "" {
+		return ValidateTelephone(r.Telephone)
+	}
+	return nil
+}
+
+// Validate checks the telephone of the contact.
+func (c *Contact) Validate() error {
+	if err := ValidateTelephone(c.Telephone); err != nil {
+		return err
+	}
+	for _, r := range c.TelephoneExtension {
+		if r < '0' || r > '9' {
+			return errors.New("The telephone extension must only contain digits: " + c.TelephoneExtension)
+		}
+	}
+	return nil
+}
+
+// Validate checks the country of the address.
+func (a *Address) Validate() error {
+	return ValidateCountry(a.Country)
+}
+
+// Validate checks the country of the shipping address.
+func (s *ShipInfo) Validate() error {
+	return s.Address.Validate()
+}
+
+// countryCodes lists the ISO 3166-1 alpha-2 codes, lower case as DigiCert returns them.
+var countryCodes = map[string]bool{
+	"ad": true, "ae": true, "af": true, "ag": true, "ai": true, "al": true, "am": true, "ao": true,
+	"aq": true, "ar": true, "as": true, "at": true, "au": true, "aw": true, "ax": true, "az": true,
+	"ba": true, "bb": true, "bd": true, "be": true, "bf": true, "bg": true, "bh": true, "bi": true,
+	"bj": true, "bl": true, "bm": true, "bn": true, "bo": true, "bq": true, "br": true, "bs": true,
+	"bt": true, "bv": true, "bw": true, "by": true, "bz": true, "ca": true, "cc": true, "cd": true,
+	"cf": true, "cg": true, "ch": true, "ci": true, "ck": true, "cl": true, "cm": true, "cn": true,
+	"co": true, "cr": true, "cu": true, "cv": true, "cw": true, "cx": true, "cy": true, "cz": true,
+	"de": true, "dj": true, "dk": true, "dm": true, "do": true, "dz": true, "ec": true, "ee": true,
+	"eg": true, "eh": true, "er": true, "es": true, "et": true, "fi": true, "fj": true, "fk": true,
+	"fm": true, "fo": true, "fr": true, "ga": true, "gb": true, "gd": true, "ge": true, "gf": true,
+	"gg": true, "gh": true, "gi": true, "gl": true, "gm": true, "gn": true, "gp": true, "gq": true,
+	"gr": true, "gs": true, "gt": true, "gu": true, "gw": true, "gy": true, "hk": true, "hm": true,
+	"hn": true, "hr": true, "ht": true, "hu": true, "id": true, "ie": true, "il": true, "im": true,
+	"in": true, "io": true, "iq": true, "ir": true, "is": true, "it": true, "je": true, "jm": true,
+	"jo": true, "jp": true, "ke": true, "kg": true, "kh": true, "ki": true, "km": true, "kn": true,
+	"kp": true, "kr": true, "kw": true, "ky": true, "kz": true, "la": true, "lb": true, "lc": true,
+	"li": true, "lk": true, "lr": true, "ls": true, "lt": true, "lu": true, "lv": true, "ly": true,
+	"ma": true, "mc": true, "md": true, "me": true, "mf": true, "mg": true, "mh": true, "mk": true,
+	"ml": true, "mm": true, "mn": true, "mo": true, "mp": true, "mq": true, "mr": true, "ms": true,
+	"mt": true, "mu": true, "mv": true, "mw": true, "mx": true, "my": true, "mz": true, "na": true,
+	"nc": true, "ne": true, "nf": true, "ng": true, "ni": true, "nl": true, "no": true, "np": true,
+	"nr": true, "nu": true, "nz": true, "om": true, "pa": true, "pe": true, "pf": true, "pg": true,
+	"ph": true, "pk": true, "pl": true, "pm": true, "pn": true, "pr": true, "ps": true, "pt": true,
+	"pw": true, "py": true, "qa": true, "re": true, "ro": true, "rs": true, "ru": true, "rw": true,
+	"sa": true, "sb": true, "sc": true, "sd": true, "se": true, "sg": true, "sh": true, "si": true,
+	"sj": true, "sk": true, "sl": true, "sm": true, "sn": true, "so": true, "sr": true, "ss": true,
+	"st": true, "sv": true, "sx": true, "sy": true, "sz": true, "tc": true, "td": true, "tf": true,
+	"tg": true, "th": true, "tj": true, "tk": true, "tl": true, "tm": true, "tn": true, "to": true,
+	"tr": true, "tt": true, "tv": true, "tw": true, "tz": true, "ua": true, "ug": true, "um": true,
+	"us": true, "uy": true, "uz": true, "va": true, "vc": true, "ve": true, "vg": true, "vi": true,
+	"vn": true, "vu": true, "wf": true, "ws": true, "ye": true, "yt": true, "za": true, "zm": true,
+	"zw": true,
+}
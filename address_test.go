@@ -0,0 +1,71 @@
+package digicert
+
+import (
+	"testing"
+)
+
+func TestValidateCountry(t *testing.T) {
+	tests := []struct {
+		code string
+		ok   bool
+	}{
+		{"gb", true},
+		{"GB", true},
+		{"Us", true},
+		{"uk", false},
+		{"gbr", false},
+		{"g", false},
+		{"", false},
+		{" gb", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateCountry(tt.code); (err == nil) != tt.ok {
+			t.Errorf("ValidateCountry(%q) = %v, want ok %v", tt.code, err, tt.ok)
+		}
+	}
+}
+
+func TestValidateTelephone(t *testing.T) {
+	tests := []struct {
+		number string
+		ok     bool
+	}{
+		{"1234567", true},
+		{"123456", false},
+		{"123456789012345", true},
+		{"1234567890123456", false},
+		{"+44 20 7946 0000", true},
+		{" +1 (202) 555-0100 ", true},
+		{"+1.202.555.0100", true},
+		{"44+20 7946 0000", false},
+		{"++44 20 7946 0000", false},
+		{"+123456", false},
+		{"+44 20 7946 0000 x123", false},
+		{"+44 20 7946 0000 ext. 123", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateTelephone(tt.number); (err == nil) != tt.ok {
+			t.Errorf("ValidateTelephone(%q) = %v, want ok %v", tt.number, err, tt.ok)
+		}
+	}
+}
+
+func TestContactValidate(t *testing.T) {
+	tests := []struct {
+		telephone, extension string
+		ok                   bool
+	}{
+		{"+44 20 7946 0000", "", true},
+		{"+44 20 7946 0000", "123", true},
+		{"+44 20 7946 0000", "x123", false},
+		{"+44 20 7946 0000", "12 3", false},
+		{"+44 20", "123", false},
+	}
+	for _, tt := range tests {
+		c := &Contact{Telephone: tt.telephone, TelephoneExtension: tt.extension}
+		if err := c.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%q ext %q) = %v, want ok %v", tt.telephone, tt.extension, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package digicert
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeyFinding presents why an API key is flagged by AuditAPIKeys.
+type APIKeyFinding string
+
+// API key audit findings.
+const (
+	// APIKeyUnused is a key not used, or never used, for the audit window.
+	APIKeyUnused APIKeyFinding = "unused"
+	// APIKeyInactiveOwner is a key whose user is not active.
+	APIKeyInactiveOwner APIKeyFinding = "inactive_owner"
+	// APIKeyDeletedOwner is a key whose user is not listed anymore, across the account.
+	APIKeyDeletedOwner APIKeyFinding = "deleted_owner"
+	// APIKeyDuplicateName is a key sharing its purpose, the name without case, spaces and punctuation, with a more recently used active key.
+	APIKeyDuplicateName APIKeyFinding = "duplicate_name"
+)
+
+// APIKeyAuditEntry presents an active API key with what is wrong with it.
+type APIKeyAuditEntry struct {
+	ID           int
+	Name         string
+	UserID       int
+	Owner        string
+	CreateDate   time.Time
+	LastUsedDate time.Time
+	Findings     []APIKeyFinding
+	// Revoked and Err report the remediation of a flagged key.
+	Revoked bool
+	Err     error
+}
+
+// Flagged reports whether the key has findings.
+func (e *APIKeyAuditEntry) Flagged() bool {
+	return len(e.Findings) > 0
+}
+
+// APIKeyAuditOptions presents what AuditAPIKeys flags and whether it remediates.
+type APIKeyAuditOptions struct {
+	// UnusedFor flags the keys not used for this long, defaults to 90 days.
+	UnusedFor time.Duration
+	// ContainerID is the container whose users (with its children) own the keys, defaults to the container of the API key user.
+	// The owners not found there are looked up across the account before they are reported deleted.
+	ContainerID string
+	// Remediate revokes the flagged keys that Confirm accepts.
+	Remediate bool
+	Confirm   func(*APIKeyAuditEntry) bool
+}
+
+// keyPurpose strips the separators of a lowercased name, e.g. deploy-prod and Deploy Prod share a purpose while prod and prod2 do not.
+var keyPurpose = regexp.MustCompile(`[^a-z0-9]+`)
+
+// AuditAPIKeys exports reviews the active API keys: keys unused for the window, owned by inactive or deleted users (by ListUsers), or sharing a purpose with a more recently used key are flagged.
+// With Remediate, every flagged key accepted by Confirm is revoked.
+func (c *Client) AuditAPIKeys(options *APIKeyAuditOptions) ([]APIKeyAuditEntry, error) {
+	if options == nil {
+		options = new(APIKeyAuditOptions)
+	}
+	if options.Remediate && options.Confirm == nil {
+		return nil, errors.New("The remediation of api keys must be confirmed")
+	}
+	unusedFor := options.UnusedFor
+	if unusedFor == 0 {
+		unusedFor = 90 * 24 * time.Hour
+	}
+	keys, err := c.ListAPIKeys()
+	if err != nil {
+		return nil, err
+	}
+	if err := keys.err(); err != nil {
+		return nil, err
+	}
+	statuses := make(map[int]string)
+	if err := c.userStatuses(options.ContainerID, statuses); err != nil {
+		return nil, err
+	}
+	if options.ContainerID != "" {
+		// A user of another container is not deleted, the owners missing from the container are looked up across the account.
+		for _, k := range keys.APIKeys {
+			if _, ok := statuses[k.User.ID]; !ok && k.Status == "active" {
+				if err := c.userStatuses("", statuses); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	cutoff := time.Now().Add(-unusedFor)
+	var entries []APIKeyAuditEntry
+	var lastUses []time.Time
+	purposes := make(map[string][]int)
+	for _, k := range keys.APIKeys {
+		if k.Status != "active" {
+			continue
+		}
+		e := APIKeyAuditEntry{
+			ID:           k.ID,
+			Name:         k.Name,
+			UserID:       k.User.ID,
+			Owner:        strings.TrimSpace(k.User.FirstName + " " + k.User.LastName),
+			CreateDate:   k.CreateDate.Time,
+			LastUsedDate: k.LastUsedDate.Time,
+		}
+		lastUsed := e.LastUsedDate
+		if lastUsed.IsZero() {
+			lastUsed = e.CreateDate
+		}
+		if lastUsed.Before(cutoff) {
+			e.Findings = append(e.Findings, APIKeyUnused)
+		}
+		switch status, ok := statuses[k.User.ID]; {
+		case !ok:
+			e.Findings = append(e.Findings, APIKeyDeletedOwner)
+		case status != "active":
+			e.Findings = append(e.Findings, APIKeyInactiveOwner)
+		}
+		purpose := keyPurpose.ReplaceAllString(strings.ToLower(rotatedSuffix.ReplaceAllString(k.Name, "")), "")
+		if purpose != "" {
+			purposes[purpose] = append(purposes[purpose], len(entries))
+		}
+		entries = append(entries, e)
+		lastUses = append(lastUses, lastUsed)
+	}
+	for _, same := range purposes {
+		if len(same) < 2 {
+			continue
+		}
+		// The most recently used key of a purpose is the one kept.
+		kept := same[0]
+		for _, i := range same[1:] {
+			if lastUses[i].After(lastUses[kept]) {
+				kept = i
+			}
+		}
+		for _, i := range same {
+			if i != kept {
+				entries[i].Findings = append(entries[i].Findings, APIKeyDuplicateName)
+			}
+		}
+	}
+
+	if options.Remediate {
+		for i := range entries {
+			e := &entries[i]
+			if e.Flagged() && options.Confirm(e) {
+				e.Err = c.RevokeAPIKey(strconv.Itoa(e.ID))
+				e.Revoked = e.Err == nil
+			}
+		}
+	}
+	return entries, nil
+}
+
+// userStatuses adds the status of the users of containerID, with its children, to statuses.
+func (c *Client) userStatuses(containerID string, statuses map[int]string) error {
+	users, err := c.ListUsers(containerID)
+	if err != nil {
+		return err
+	}
+	if err := users.err(); err != nil {
+		return err
+	}
+	for _, u := range users.Users {
+		statuses[u.ID] = u.Status
+	}
+	return nil
+}
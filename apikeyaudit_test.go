@@ -0,0 +1,111 @@
+package digicert
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAuditAPIKeys(t *testing.T) {
+	api, c := newFakeAPI(t)
+	recent := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+	older := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+	key := func(id int, name string, userID int, lastUsed string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": name, "status": "active", "create_date": older, "last_used_date": lastUsed, "user": map[string]int{"id": userID}}
+	}
+	api.reply("GET /key", 200, map[string]interface{}{"api_keys": []interface{}{
+		key(1, "deploy-prod", 7, older),
+		key(2, "Deploy Prod", 7, recent),
+		key(3, "deploy_prod", 7, older),
+		// Numbered keys are kept apart.
+		key(6, "app1", 7, older),
+		key(7, "app2", 7, older),
+		key(10, "prod", 7, recent),
+		key(11, "prod2", 7, recent),
+		// The owner is in another container of the account.
+		key(4, "backup", 8, recent),
+		key(5, "monitoring", 9, recent),
+	}})
+	api.handle("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		users := []map[string]interface{}{{"id": 7, "status": "active"}}
+		if r.URL.Query().Get("container_id") == "" {
+			users = append(users, map[string]interface{}{"id": 8, "status": "active"})
+		}
+		writeJSON(w, 200, map[string]interface{}{"users": users})
+	})
+
+	entries, err := c.AuditAPIKeys(&APIKeyAuditOptions{ContainerID: "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int][]APIKeyFinding{
+		1: {APIKeyDuplicateName},
+		2: nil,
+		3: {APIKeyDuplicateName},
+		4: nil,
+		5: {APIKeyDeletedOwner},
+		6: nil, 7: nil, 10: nil, 11: nil,
+	}
+	for _, e := range entries {
+		if !reflect.DeepEqual(e.Findings, want[e.ID]) {
+			t.Errorf("The findings of key %d are %v, want %v", e.ID, e.Findings, want[e.ID])
+		}
+	}
+	if got := api.requests(); !contains(got, "GET /user?container_id=2") || !contains(got, "GET /user?container_id=") {
+		t.Errorf("The users were listed with %v", got)
+	}
+}
+
+func TestAuditAPIKeysRemediate(t *testing.T) {
+	api, c := newFakeAPI(t)
+	old := time.Now().Add(-200 * 24 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+	api.reply("GET /key", 200, map[string]interface{}{"api_keys": []map[string]interface{}{
+		{"id": 1, "name": "legacy", "status": "active", "create_date": old, "user": map[string]int{"id": 7}},
+		{"id": 2, "name": "orphan", "status": "active", "create_date": old, "last_used_date": recent, "user": map[string]int{"id": 9}},
+		{"id": 3, "name": "ci", "status": "active", "create_date": old, "last_used_date": recent, "user": map[string]int{"id": 7}},
+		{"id": 4, "name": "broken", "status": "active", "create_date": old, "user": map[string]int{"id": 7}},
+	}})
+	api.reply("GET /user", 200, map[string]interface{}{"users": []map[string]interface{}{{"id": 7, "status": "active"}}})
+	api.reply("PUT /key/1/status", 204, nil)
+	api.reply("PUT /key/4/status", 400, map[string]interface{}{"errors": []map[string]string{{"code": "error", "message": "Unavailable."}}})
+
+	if _, err := c.AuditAPIKeys(&APIKeyAuditOptions{Remediate: true}); err == nil {
+		t.Error("AuditAPIKeys remediates without Confirm")
+	}
+
+	var confirmed []int
+	entries, err := c.AuditAPIKeys(&APIKeyAuditOptions{Remediate: true, Confirm: func(e *APIKeyAuditEntry) bool {
+		confirmed = append(confirmed, e.ID)
+		// The orphan key is declined.
+		return e.ID != 2
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(confirmed, []int{1, 2, 4}) {
+		t.Errorf("Confirm was asked for %v, want the flagged keys [1 2 4]", confirmed)
+	}
+	byID := make(map[int]APIKeyAuditEntry)
+	for _, e := range entries {
+		byID[e.ID] = e
+	}
+	if e := byID[1]; !e.Revoked || e.Err != nil {
+		t.Errorf("key 1 = %+v, want revoked", e)
+	}
+	if e := byID[2]; e.Revoked || e.Err != nil || api.count("PUT /key/2/status") != 0 {
+		t.Errorf("key 2 = %+v, want it kept after the decline", e)
+	}
+	if e := byID[3]; e.Revoked || api.count("PUT /key/3/status") != 0 {
+		t.Errorf("key 3 = %+v, want an unflagged key kept", e)
+	}
+	if e := byID[4]; e.Revoked || e.Err == nil {
+		t.Errorf("key 4 = %+v, want the revocation error", e)
+	}
+	var status APIKeyStatus
+	api.body("PUT /key/1/status", &status)
+	if status.Status != "revoked" {
+		t.Errorf("The key 1 status was set to %q", status.Status)
+	}
+}
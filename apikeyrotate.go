@@ -0,0 +1,109 @@
+package digicert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// APIKeySink receives the replacement key of RotateAPIKey, e.g. to store it in a secret manager, before the old key is revoked.
+type APIKeySink func(ctx context.Context, keyID int, apiKey string) error
+
+// RotatedAPIKey presents the outcome of RotateAPIKey.
+type RotatedAPIKey struct {
+	OldID int
+	NewID int
+	Name  string
+	// OldRevoked is false when the old key could not be revoked after the new one was handed over, both keys are then valid.
+	OldRevoked bool
+}
+
+// rotatedSuffix matches the suffix added to the names of rotated keys.
+var rotatedSuffix = regexp.MustCompile(` \(rotated \d{8}-\d{6}\)$`)
+
+// RotateAPIKey exports replaces the API key keyID of a running service: it creates a key for the same user with a derived name, checks the new key works with a lightweight call, hands it to sink, then revokes the old key.
+// Every call is canceled with ctx. When a step fails or ctx is done before sink accepted the new key, the new key is revoked, regardless of ctx, and the old one stays in use.
+// When only the revocation of the old key fails, the result is returned with OldRevoked false along with the error.
+func (c *Client) RotateAPIKey(ctx context.Context, keyID string, sink APIKeySink) (*RotatedAPIKey, error) {
+	if sink == nil {
+		return nil, errors.New("The secret sink of the new api key must input")
+	}
+	client := c.withContext(ctx)
+	old, err := client.ViewAPIKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := old.err(); err != nil {
+		return nil, err
+	}
+	if old.Status != "active" {
+		return nil, errors.New("The api key " + keyID + " is " + old.Status + ", only active keys are rotated")
+	}
+	rotated := &RotatedAPIKey{
+		OldID: old.ID,
+		Name:  rotatedSuffix.ReplaceAllString(old.Name, "") + " (rotated " + time.Now().UTC().Format("20060102-150405") + ")",
+	}
+
+	created, err := client.NewAPIKey(strconv.Itoa(old.User.ID), rotated.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := created.err(); err != nil {
+		return nil, err
+	}
+	rotated.NewID = created.ID
+	newID := strconv.Itoa(created.ID)
+	rollback := func(cause error) (*RotatedAPIKey, error) {
+		// The new key is revoked even when ctx is done, it must not stay valid unknown to anyone.
+		if err := c.clone().RevokeAPIKey(newID); err != nil {
+			return nil, errors.New(cause.Error() + "; the new api key " + newID + " was not revoked: " + err.Error())
+		}
+		return nil, cause
+	}
+
+	verify := c.withContext(ctx)
+	verify.AuthKey, verify.Credentials = created.APIKey, nil
+	check, err := verify.ViewAPIKey(newID)
+	if err == nil {
+		err = check.err()
+	}
+	if err != nil {
+		return rollback(errors.New("The new api key does not work: " + err.Error()))
+	}
+	if err := ctx.Err(); err != nil {
+		return rollback(err)
+	}
+	if err := sink(ctx, created.ID, created.APIKey); err != nil {
+		return rollback(errors.New("The new api key was not accepted by the sink: " + err.Error()))
+	}
+
+	if err := client.RevokeAPIKey(keyID); err != nil {
+		return rotated, errors.New("The old api key " + keyID + " was not revoked: " + err.Error())
+	}
+	rotated.OldRevoked = true
+	return rotated, nil
+}
+
+// RevokeAPIKey exports revokes the specified API key, it can no longer be used.
+func (c *Client) RevokeAPIKey(keyID string) error {
+	c.request = &APIKeyStatus{
+		Status: "revoked",
+	}
+	data, err := c.makeRequest("PUT", "/key/"+keyID+"/status", nil)
+	if err != nil {
+		return err
+	}
+	if c.statusCode == 204 {
+		return nil
+	}
+	var res SchemeValidationErrors
+	if err := json.Unmarshal(data, &res); err == nil {
+		if err := res.err(); err != nil {
+			return err
+		}
+	}
+	return errors.New("The api key was not revoked, status " + strconv.Itoa(c.statusCode))
+}
@@ -0,0 +1,140 @@
+package digicert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRotateAPI answers the calls of rotating key 1 of user 7 into key 2.
+func newRotateAPI(t *testing.T) (*fakeAPI, *Client) {
+	api, c := newFakeAPI(t)
+	api.acceptKey("new-key")
+	api.reply("GET /key/1", 200, map[string]interface{}{"id": 1, "name": "billing (rotated 20200101-000000)", "status": "active", "user": map[string]int{"id": 7}})
+	api.reply("POST /key/user/7", 201, map[string]interface{}{"id": 2, "api_key": "new-key"})
+	api.handle("GET /key/2", func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("X-DC-DEVKEY"); key != "new-key" {
+			t.Errorf("The new key was checked with %q", key)
+		}
+		writeJSON(w, 200, map[string]interface{}{"id": 2, "status": "active"})
+	})
+	return api, c
+}
+
+// revoked returns the status sent for keyID, "" when it was not changed.
+func revoked(api *fakeAPI, keyID string) string {
+	if api.count("PUT /key/"+keyID+"/status") == 0 {
+		return ""
+	}
+	var status APIKeyStatus
+	api.body("PUT /key/"+keyID+"/status", &status)
+	return status.Status
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	api, c := newRotateAPI(t)
+	api.reply("PUT /key/1/status", 204, nil)
+	var stored string
+	rotated, err := c.RotateAPIKey(context.Background(), "1", func(ctx context.Context, keyID int, apiKey string) error {
+		if api.count("PUT /key/1/status") != 0 {
+			t.Error("The old key was revoked before the sink stored the new one")
+		}
+		stored = apiKey
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.OldID != 1 || rotated.NewID != 2 || !rotated.OldRevoked || stored != "new-key" {
+		t.Errorf("RotateAPIKey = %+v, stored %q", rotated, stored)
+	}
+	if !strings.HasPrefix(rotated.Name, "billing (rotated ") || strings.Count(rotated.Name, "rotated") != 1 {
+		t.Errorf("The new key is named %q", rotated.Name)
+	}
+	if status := revoked(api, "1"); status != "revoked" {
+		t.Errorf("The old key status was set to %q", status)
+	}
+}
+
+func TestRotateAPIKeySinkFails(t *testing.T) {
+	api, c := newRotateAPI(t)
+	api.reply("PUT /key/2/status", 204, nil)
+	rotated, err := c.RotateAPIKey(context.Background(), "1", func(ctx context.Context, keyID int, apiKey string) error {
+		return errors.New("vault sealed")
+	})
+	if rotated != nil || err == nil || !strings.Contains(err.Error(), "vault sealed") {
+		t.Fatalf("RotateAPIKey = %+v, %v, want the sink error", rotated, err)
+	}
+	if status := revoked(api, "2"); status != "revoked" {
+		t.Errorf("The new key status was set to %q, want revoked", status)
+	}
+	if status := revoked(api, "1"); status != "" {
+		t.Errorf("The old key status was set to %q, want it left in use", status)
+	}
+}
+
+func TestRotateAPIKeyNewKeyNotRevoked(t *testing.T) {
+	api, c := newRotateAPI(t)
+	api.reply("PUT /key/2/status", 400, map[string]interface{}{"errors": []map[string]string{{"code": "error", "message": "Unavailable."}}})
+	_, err := c.RotateAPIKey(context.Background(), "1", func(ctx context.Context, keyID int, apiKey string) error {
+		return errors.New("vault sealed")
+	})
+	if err == nil || !strings.Contains(err.Error(), "vault sealed") || !strings.Contains(err.Error(), "new api key 2 was not revoked") {
+		t.Fatalf("RotateAPIKey error = %v, want both errors", err)
+	}
+}
+
+func TestRotateAPIKeyOldRevokeFails(t *testing.T) {
+	api, c := newRotateAPI(t)
+	api.reply("PUT /key/1/status", 400, map[string]interface{}{"errors": []map[string]string{{"code": "error", "message": "Unavailable."}}})
+	rotated, err := c.RotateAPIKey(context.Background(), "1", func(ctx context.Context, keyID int, apiKey string) error {
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "old api key 1 was not revoked") {
+		t.Fatalf("RotateAPIKey error = %v, want the revocation error", err)
+	}
+	if rotated == nil || rotated.NewID != 2 || rotated.OldRevoked {
+		t.Errorf("RotateAPIKey = %+v, want the new key with the old one not revoked", rotated)
+	}
+	if status := revoked(api, "2"); status != "" {
+		t.Errorf("The new key status was set to %q after the sink stored it", status)
+	}
+}
+
+func TestRotateAPIKeyCanceled(t *testing.T) {
+	api, c := newRotateAPI(t)
+	api.handle("POST /key/user/7", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Error("The key creation was not canceled with the context")
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.RotateAPIKey(ctx, "1", func(context.Context, int, string) error { return nil }); err == nil {
+		t.Fatal("RotateAPIKey succeeded with a canceled context")
+	}
+
+	// A rotation canceled once the key exists still revokes it.
+	api, c = newRotateAPI(t)
+	api.reply("PUT /key/2/status", 204, nil)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	_, err := c.RotateAPIKey(ctx, "1", func(ctx context.Context, keyID int, apiKey string) error {
+		cancel()
+		return ctx.Err()
+	})
+	if err == nil {
+		t.Fatal("RotateAPIKey succeeded with a canceled context")
+	}
+	if status := revoked(api, "2"); status != "revoked" {
+		t.Errorf("The new key status was set to %q, want revoked", status)
+	}
+	if status := revoked(api, "1"); status != "" {
+		t.Errorf("The old key status was set to %q, want it left in use", status)
+	}
+}
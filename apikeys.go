@@ -3,7 +3,6 @@ package digicert
 import (
 	"encoding/json"
 	"log"
-	"time"
 )
 
 // APIKeyStatus present API Key status request
@@ -11,20 +10,23 @@ type APIKeyStatus struct {
 	Status string `json:"status"`
 }
 
+// ListedAPIKey represents an api key of ListAPIKeys
+type ListedAPIKey struct {
+	CreateDate   DateTime `json:"create_date"`
+	ID           int      `json:"id"`
+	LastUsedDate DateTime `json:"last_used_date"`
+	Name         string   `json:"name"`
+	Status       string   `json:"status"`
+	User         struct {
+		FirstName string `json:"first_name"`
+		ID        int    `json:"id"`
+		LastName  string `json:"last_name"`
+	} `json:"user"`
+}
+
 //ListAPIKeysResponse represents an api keys details
 type ListAPIKeysResponse struct {
-	APIKeys []struct {
-		CreateDate   string `json:"create_date"`
-		ID           int    `json:"id"`
-		LastUsedDate string `json:"last_used_date"`
-		Name         string `json:"name"`
-		Status       string `json:"status"`
-		User         struct {
-			FirstName string `json:"first_name"`
-			ID        int    `json:"id"`
-			LastName  string `json:"last_name"`
-		} `json:"user"`
-	} `json:"api_keys"`
+	APIKeys []ListedAPIKey `json:"api_keys"`
 	SchemeValidationErrors
 }
 
@@ -48,10 +50,10 @@ type ViewAPIKeyResponse struct {
 		FirstName string `json:"first_name"`
 		LastName  string `json:"last_name"`
 	} `json:"user"`
-	Status       string    `json:"status"`
-	CreateDate   time.Time `json:"create_date"`
-	LastUsedDate time.Time `json:"last_used_date"`
-	Name         string    `json:"name"`
+	Status       string   `json:"status"`
+	CreateDate   DateTime `json:"create_date"`
+	LastUsedDate DateTime `json:"last_used_date"`
+	Name         string   `json:"name"`
 	SchemeValidationErrors
 }
 
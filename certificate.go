@@ -1,11 +1,12 @@
 package digicert
 
 import (
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"net/http"
 	"strconv"
-	"time"
 )
 
 // DownloadCertificateResponse exports download certificate
@@ -22,17 +23,31 @@ type DownloadCertificateResponse struct {
 	SchemeValidationErrors
 }
 
-// RevokeCertificateRequest exports the request comment
+// RevocationReason presents an RFC 5280 reason code accepted by the revoke endpoints.
+type RevocationReason string
+
+// Revocation reasons, see RFC 5280 section 5.3.1.
+const (
+	ReasonUnspecified          RevocationReason = "unspecified"
+	ReasonKeyCompromise        RevocationReason = "key_compromise"
+	ReasonAffiliationChanged   RevocationReason = "affiliation_changed"
+	ReasonSuperseded           RevocationReason = "superseded"
+	ReasonCessationOfOperation RevocationReason = "cessation_of_operation"
+)
+
+// RevokeCertificateRequest exports the request comment and revocation reason
 type RevokeCertificateRequest struct {
-	Comment string
+	Comment          string           `json:"comments"`
+	RevocationReason RevocationReason `json:"revocation_reason,omitempty"`
+	SkipApproval     bool             `json:"skip_approval,omitempty"`
 }
 
 // RevokeCertificateResponse exports revoke response
 type RevokeCertificateResponse struct {
-	ID        int       `json:"id"`
-	Date      time.Time `json:"date"`
-	Type      string    `json:"type"`
-	Status    string    `json:"status"`
+	ID        int           `json:"id"`
+	Date      DateTime      `json:"date"`
+	Type      string        `json:"type"`
+	Status    RequestStatus `json:"status"`
 	Requester struct {
 		ID        int    `json:"id"`
 		FirstName string `json:"first_name"`
@@ -45,9 +60,9 @@ type RevokeCertificateResponse struct {
 
 // CancelRequest presents a certificate cancel request
 type CancelRequest struct {
-	Status     string `json:"status"`
-	Note       string `json:"note"`
-	SendEmails bool   `json:"send_emails"`
+	Status     OrderStatus `json:"status"`
+	Note       string      `json:"note"`
+	SendEmails bool        `json:"send_emails"`
 }
 
 // ReissueRequest presents a reissue certificate
@@ -59,7 +74,7 @@ type ReissueRequest struct {
 		ServerPlatform struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 }
 
@@ -81,7 +96,7 @@ type DuplicateRequest struct {
 		ServerPlatform struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 }
 
@@ -98,27 +113,27 @@ type DuplicateResponse struct {
 // ListDuplicateResponse presents all duplicate certificates.
 type ListDuplicateResponse struct {
 	Certificates []struct {
-		ID             int       `json:"id"`
-		Thumbprint     string    `json:"thumbprint"`
-		SerialNumber   string    `json:"serial_number"`
-		CommonName     string    `json:"common_name"`
-		DNSNames       []string  `json:"dns_names"`
-		Status         string    `json:"status"`
-		DateCreated    time.Time `json:"date_created"`
-		ValidFrom      string    `json:"valid_from"`
-		ValidTill      string    `json:"valid_till"`
-		Csr            string    `json:"csr"`
+		ID             int         `json:"id"`
+		Thumbprint     string      `json:"thumbprint"`
+		SerialNumber   string      `json:"serial_number"`
+		CommonName     string      `json:"common_name"`
+		DNSNames       []string    `json:"dns_names"`
+		Status         OrderStatus `json:"status"`
+		DateCreated    DateTime    `json:"date_created"`
+		ValidFrom      Date        `json:"valid_from"`
+		ValidTill      Date        `json:"valid_till"`
+		Csr            string      `json:"csr"`
 		ServerPlatform struct {
 			ID         int    `json:"id"`
 			Name       string `json:"name"`
 			InstallURL string `json:"install_url"`
 			CsrURL     string `json:"csr_url"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		KeySize       int    `json:"key_size"`
-		CaCertID      string `json:"ca_cert_id"`
-		SubID         string `json:"sub_id"`
-		PublicID      string `json:"public_id"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		KeySize       int           `json:"key_size"`
+		CaCertID      string        `json:"ca_cert_id"`
+		SubID         string        `json:"sub_id"`
+		PublicID      string        `json:"public_id"`
 	} `json:"certificates"`
 
 	SchemeValidationErrors
@@ -127,9 +142,9 @@ type ListDuplicateResponse struct {
 // OrderStatusResponse presents a orderstatus within minutes.
 type OrderStatusResponse struct {
 	Orders []struct {
-		OrderID       int    `json:"order_id"`
-		CertificateID int    `json:"certificate_id"`
-		Status        string `json:"status"`
+		OrderID       int         `json:"order_id"`
+		CertificateID int         `json:"certificate_id"`
+		Status        OrderStatus `json:"status"`
 	} `json:"orders"`
 
 	SchemeValidationErrors
@@ -137,19 +152,21 @@ type OrderStatusResponse struct {
 
 // DVChangeDCVMethodRequest presents changing DCV method.
 type DVChangeDCVMethodRequest struct {
-	DcvMethod string `json:"dcv_method"`
+	DcvMethod DCVMethod `json:"dcv_method"`
 }
 
 // DVRandomValue presents changing DCV method response.
 type DVRandomValue struct {
 	DcvRandomValue string `json:"dcv_random_value"`
+
+	SchemeValidationErrors
 }
 
 // DVCheckDCVResponse presents checking dcv response.
 type DVCheckDCVResponse struct {
-	OrderStatus   string `json:"order_status"`
-	CertificateID int    `json:"certificate_id"`
-	DcvStatus     string `json:"dcv_status"`
+	OrderStatus   OrderStatus `json:"order_status"`
+	CertificateID int         `json:"certificate_id"`
+	DcvStatus     DCVStatus   `json:"dcv_status"`
 
 	SchemeValidationErrors
 }
@@ -182,11 +199,55 @@ func (c *Client) DownloadPKCS7Certificate(certificateID string) (string, error)
 	return string(data), nil
 }
 
+// DownloadCertificateChain exports method download the certificate with its intermediates and root, and parse them leaf first.
+func (c *Client) DownloadCertificateChain(certificateID string) ([]*x509.Certificate, error) {
+	headers := make(http.Header)
+	headers.Set("Content-Type", "application/x-pem-file")
+	data, err := c.makeRequest("GET", "/certificate/"+certificateID+"/download/format/pem_all", headers)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCertificateChain(string(data))
+}
+
+// ParseCertificateChain exports parses a PEM bundle as returned by the download endpoints, the certificates keep the order of the bundle.
+func ParseCertificateChain(bundle string) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("There is no certificate in the PEM bundle")
+	}
+	return chain, nil
+}
+
 // Revoke exports method revoke the certificate
 func (c *Client) Revoke(certificateID, comment string) (*RevokeCertificateResponse, error) {
-	c.request = &RevokeCertificateRequest{
+	return c.RevokeCertificate(certificateID, &RevokeCertificateRequest{
 		Comment: comment,
+	})
+}
+
+// RevokeCertificate exports Use this endpoint to revoke a single certificate of an order, the other certificates (duplicates) on the order are left untouched.
+func (c *Client) RevokeCertificate(certificateID string, request *RevokeCertificateRequest) (*RevokeCertificateResponse, error) {
+	if err := request.RevocationReason.validate(); err != nil {
+		return nil, err
 	}
+	c.request = request
 	c.result = new(RevokeCertificateResponse)
 	data, err := c.makeRequest("PUT", "/certificate/"+certificateID+"/revoke", nil)
 	if err != nil {
@@ -198,10 +259,36 @@ func (c *Client) Revoke(certificateID, comment string) (*RevokeCertificateRespon
 	return c.result.(*RevokeCertificateResponse), err
 }
 
-// Cancel to update the status of an order. Currently this endpoint only allows updating the status to 'CANCELED'
+// RevokeOrder exports Use this endpoint to revoke all certificates (including duplicates and reissues) of an order.
+func (c *Client) RevokeOrder(orderID string, request *RevokeCertificateRequest) (*RevokeCertificateResponse, error) {
+	if err := request.RevocationReason.validate(); err != nil {
+		return nil, err
+	}
+	c.request = request
+	c.result = new(RevokeCertificateResponse)
+	data, err := c.makeRequest("PUT", "/order/certificate/"+orderID+"/revoke", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.result); err != nil {
+		return nil, err
+	}
+	return c.result.(*RevokeCertificateResponse), err
+}
+
+// validate checks the reason is one of the RFC 5280 reasons accepted by DigiCert, empty leaves the reason to the API default.
+func (r RevocationReason) validate() error {
+	switch r {
+	case "", ReasonUnspecified, ReasonKeyCompromise, ReasonAffiliationChanged, ReasonSuperseded, ReasonCessationOfOperation:
+		return nil
+	}
+	return errors.New("The revocation reason is not accepted")
+}
+
+// Cancel to update the status of an order. Currently this endpoint only allows updating the status to OrderCanceled, 'canceled'
 func (c *Client) Cancel(orderID, comment string) (bool, error) {
 	c.request = &CancelRequest{
-		Status:     "CANCELED",
+		Status:     OrderCanceled,
 		Note:       comment,
 		SendEmails: true,
 	}
@@ -257,6 +344,22 @@ func (c *Client) ListDuplicateCertificates(orderID string) (*ListDuplicateRespon
 	return c.result.(*ListDuplicateResponse), err
 }
 
+// ListReissueResponse presents the reissued certificates of an order, listed as the duplicates.
+type ListReissueResponse ListDuplicateResponse
+
+// ListReissueCertificates exports view all reissued certificates for an order, the current certificate is in ViewOrder.
+func (c *Client) ListReissueCertificates(orderID string) (*ListReissueResponse, error) {
+	c.result = new(ListReissueResponse)
+	data, err := c.makeRequest("GET", "/order/certificate/"+orderID+"/reissue", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.result); err != nil {
+		return nil, err
+	}
+	return c.result.(*ListReissueResponse), err
+}
+
 // ListOrganizationsRequest presents retrive a list of organizations
 type ListOrganizationsRequest struct {
 	Organizations []struct {
@@ -283,9 +386,9 @@ func (c *Client) ListOrganizations(containerID string) (*ListOrganizationsReques
 type ListEmailValidationsResponse struct {
 	DeliveryOptions []string `json:"delivery_options"`
 	Emails          []struct {
-		Email       string `json:"email"`
-		Status      string `json:"status"`
-		DateEmailed string `json:"date_emailed"`
+		Email       string   `json:"email"`
+		Status      string   `json:"status"`
+		DateEmailed DateTime `json:"date_emailed"`
 	} `json:"emails"`
 }
 
@@ -316,14 +419,9 @@ func (c *Client) OrderStatus(minutes int) (*OrderStatusResponse, error) {
 }
 
 // DVChangeDCVMethod exports Use this endpoint on pending DV SSL orders to change the DCV method to use to prove control over the domain on the order. Method: email, dns-txt-token, http-token
-func (c *Client) DVChangeDCVMethod(orderID, method string) (*DVRandomValue, error) {
+func (c *Client) DVChangeDCVMethod(orderID string, method DCVMethod) (*DVRandomValue, error) {
 	switch method {
-	case "email":
-		method = "email"
-	case "dns-txt-token":
-		method = "dns-txt-token"
-	case "http-token":
-		method = "http-token"
+	case DCVEmail, DCVDNSTXTToken, DCVHTTPToken:
 	default:
 		return nil, errors.New("The wrong method")
 	}
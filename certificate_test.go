@@ -0,0 +1,18 @@
+package digicert
+
+import (
+	"testing"
+)
+
+func TestCancel(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /order/certificate/5/status", 204, nil)
+	if ok, err := c.Cancel("5", "ordered twice"); err != nil || !ok {
+		t.Fatalf("Cancel = %v, %v", ok, err)
+	}
+	var body map[string]interface{}
+	api.body("PUT /order/certificate/5/status", &body)
+	if body["status"] != "canceled" || body["note"] != "ordered twice" || body["send_emails"] != true {
+		t.Errorf("The cancel request sent %v", body)
+	}
+}
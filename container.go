@@ -2,23 +2,25 @@ package digicert
 
 import (
 	"encoding/json"
-	"time"
 )
 
 // NewContainerRequest represents a new request of container detail
 type NewContainerRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	TemplateID  int    `json:"template_id"`
-	User        struct {
-		Username    string `json:"username"`
-		FirstName   string `json:"first_name"`
-		LastName    string `json:"last_name"`
-		Email       string `json:"email"`
-		AccessRoles []struct {
-			ID int `json:"id"`
-		} `json:"access_roles"`
-	} `json:"user"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	TemplateID  int           `json:"template_id"`
+	User        ContainerUser `json:"user"`
+	// AllowedDomainNames restricts the domains that can be ordered in the container.
+	AllowedDomainNames []string `json:"allowed_domain_names,omitempty"`
+}
+
+// ContainerUser represents the initial user of a new container
+type ContainerUser struct {
+	Username    string        `json:"username"`
+	FirstName   string        `json:"first_name"`
+	LastName    string        `json:"last_name"`
+	Email       string        `json:"email"`
+	AccessRoles []IDReference `json:"access_roles"`
 }
 
 // NewContainerResponse represents a new container ID
@@ -41,9 +43,9 @@ type ViewContainerDetails struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
 	} `json:"access_roles"`
-	DateCreated string `json:"date_created"`
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
+	DateCreated DateTime `json:"date_created"`
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
 
 	SchemeValidationErrors
 }
@@ -51,9 +53,9 @@ type ViewContainerDetails struct {
 // ListContainerTempaltesResponse represents the container templates
 type ListContainerTempaltesResponse struct {
 	ContainerTemplates []struct {
-		ID          int       `json:"id"`
-		Name        string    `json:"name"`
-		DateCreated time.Time `json:"date_created"`
+		ID          int      `json:"id"`
+		Name        string   `json:"name"`
+		DateCreated DateTime `json:"date_created"`
 	} `json:"container_templates"`
 
 	SchemeValidationErrors
@@ -61,13 +63,15 @@ type ListContainerTempaltesResponse struct {
 
 // ViewAContainerTempl presents a container template
 type ViewAContainerTempl struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	DateCreated time.Time `json:"date_created"`
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	DateCreated DateTime `json:"date_created"`
 	AccessRoles []struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
 	} `json:"access_roles"`
+
+	SchemeValidationErrors
 }
 
 // ListChilContainersResponse presents a list children containers of a container
@@ -131,29 +135,21 @@ func (c *Client) UpdateAContainer(containerID string, request *UpdateAContainerR
 }
 
 // DeactiveContainer exports deactivates the given container and all its children.
+// It returns false with the API error when the status is not changed, where it used to return a nil error.
+//
+// Deprecated: use SetContainerStatus, which can preview the affected users, orders and domains first.
 func (c *Client) DeactiveContainer(containerID string) (bool, error) {
-	_, err := c.makeRequest("PUT", "/container/"+containerID+"/deactivate", nil)
-	if err != nil {
-		return false, err
-	}
-
-	if c.statusCode == 204 {
-		return true, err
-	}
-	return false, err
+	_, err := c.SetContainerStatus(containerID, ContainerInactive, nil)
+	return err == nil, err
 }
 
 // ActiveContainer exports activates the given container and all its children.
+// It returns false with the API error when the status is not changed, where it used to return a nil error.
+//
+// Deprecated: use SetContainerStatus.
 func (c *Client) ActiveContainer(containerID string) (bool, error) {
-	_, err := c.makeRequest("PUT", "/container/"+containerID+"/active", nil)
-	if err != nil {
-		return false, err
-	}
-
-	if c.statusCode == 204 {
-		return true, err
-	}
-	return false, err
+	_, err := c.SetContainerStatus(containerID, ContainerActive, nil)
+	return err == nil, err
 }
 
 // ViewContainer exports information about a specific container can be retrieved through this endpoint, including its name, description, template, and parent container id.
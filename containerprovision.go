@@ -0,0 +1,152 @@
+package digicert
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ContainerSpec presents a container to provision under an existing parent container.
+type ContainerSpec struct {
+	ParentID    string
+	Name        string
+	Description string
+	// Template is the name of a container template available in the parent container.
+	Template string
+	Admin    ContainerAdmin
+	// AllowedDomainNames restricts the domains that can be ordered in the container.
+	AllowedDomainNames []string
+	// Organizations are created in the new container.
+	Organizations []NewOrganizationRequest
+}
+
+// ContainerAdmin presents the initial user of a provisioned container, with access role names of the template.
+type ContainerAdmin struct {
+	Username  string
+	FirstName string
+	LastName  string
+	Email     string
+	Roles     []string
+}
+
+// ProvisionedContainer presents what ProvisionContainer created.
+type ProvisionedContainer struct {
+	ID              int
+	TemplateID      int
+	OrganizationIDs []int
+}
+
+// ProvisionContainer exports creates a container with its admin user and organizations as a single operation.
+// The template is resolved by name with ListContainerTempaltes and the admin roles are checked against ViewAContainerTempl before anything is created. When a later step fails, the created organizations and container are deactivated and the error is returned.
+func (c *Client) ProvisionContainer(spec *ContainerSpec) (*ProvisionedContainer, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	templates, err := c.ListContainerTempaltes(spec.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := templates.err(); err != nil {
+		return nil, err
+	}
+	templateID := 0
+	for _, t := range templates.ContainerTemplates {
+		if strings.EqualFold(t.Name, spec.Template) {
+			templateID = t.ID
+			break
+		}
+	}
+	if templateID == 0 {
+		return nil, errors.New("The container template " + spec.Template + " is not available")
+	}
+	template, err := c.ViewAContainerTempl(spec.ParentID, strconv.Itoa(templateID))
+	if err != nil {
+		return nil, err
+	}
+	if err := template.err(); err != nil {
+		return nil, err
+	}
+	roles := make(map[string]int)
+	for _, r := range template.AccessRoles {
+		roles[strings.ToLower(r.Name)] = r.ID
+	}
+	roleIDs, err := resolveRoles(spec.Admin.Roles, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &NewContainerRequest{
+		Name:               spec.Name,
+		Description:        spec.Description,
+		TemplateID:         templateID,
+		AllowedDomainNames: spec.AllowedDomainNames,
+	}
+	request.User = ContainerUser{
+		Username:  spec.Admin.Username,
+		FirstName: spec.Admin.FirstName,
+		LastName:  spec.Admin.LastName,
+		Email:     spec.Admin.Email,
+	}
+	for _, id := range roleIDs {
+		request.User.AccessRoles = append(request.User.AccessRoles, IDReference{ID: id})
+	}
+	container, err := c.NewContainer(spec.ParentID, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := container.err(); err != nil {
+		return nil, err
+	}
+	provisioned := &ProvisionedContainer{ID: container.ID, TemplateID: templateID}
+
+	for i := range spec.Organizations {
+		org := spec.Organizations[i]
+		org.Container = &IDReference{ID: container.ID}
+		res, err := c.NewOrganization(&org)
+		if err == nil {
+			err = res.err()
+		}
+		if err != nil {
+			return provisioned, c.rollbackContainer(provisioned, errors.New("The organization "+org.Name+" was not created: "+err.Error()))
+		}
+		provisioned.OrganizationIDs = append(provisioned.OrganizationIDs, res.ID)
+	}
+	return provisioned, nil
+}
+
+// rollbackContainer deactivates what was provisioned and returns cause, with the deactivations that failed.
+func (c *Client) rollbackContainer(p *ProvisionedContainer, cause error) error {
+	msg := cause.Error()
+	for _, id := range p.OrganizationIDs {
+		ok, err := c.DeactiveOrganization(strconv.Itoa(id))
+		if err == nil && !ok {
+			err = errors.New("DigiCert did not confirm the deactivation")
+		}
+		if err != nil {
+			msg += "; rollback failed, the organization " + strconv.Itoa(id) + " was not deactivated: " + err.Error()
+		}
+	}
+	if _, err := c.SetContainerStatus(strconv.Itoa(p.ID), ContainerInactive, nil); err != nil {
+		msg += "; rollback failed, the container " + strconv.Itoa(p.ID) + " was not deactivated: " + err.Error()
+	}
+	return errors.New(msg)
+}
+
+func (s *ContainerSpec) validate() error {
+	switch {
+	case s == nil || s.ParentID == "":
+		return errors.New("The parent container must input")
+	case s.Name == "":
+		return errors.New("The container name must input")
+	case s.Template == "":
+		return errors.New("The container template must input")
+	case s.Admin.Username == "" || s.Admin.Email == "":
+		return errors.New("The username and email of the container admin must input")
+	}
+	for i := range s.Organizations {
+		if err := s.Organizations[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
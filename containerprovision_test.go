@@ -0,0 +1,145 @@
+package digicert
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newProvisionAPI answers the template calls of parent container 1, whose Division template offers the Administrator and User roles.
+func newProvisionAPI(t *testing.T) (*fakeAPI, *Client) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /container/1/template", 200, map[string]interface{}{"container_templates": []map[string]interface{}{{"id": 5, "name": "Division"}}})
+	api.reply("GET /container/1/template/5", 200, map[string]interface{}{"id": 5, "name": "Division", "access_roles": []map[string]interface{}{
+		{"id": 3, "name": "Administrator"},
+		{"id": 4, "name": "User"},
+	}})
+	return api, c
+}
+
+func provisionSpec(orgs ...string) *ContainerSpec {
+	spec := &ContainerSpec{
+		ParentID: "1",
+		Name:     "EMEA",
+		Template: "division",
+		Admin:    ContainerAdmin{Username: "ada", FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Roles: []string{"Administrator"}},
+	}
+	for _, name := range orgs {
+		spec.Organizations = append(spec.Organizations, NewOrganizationRequest{
+			Name:                name,
+			Address:             Address{Line1: "1 Main St", City: "London", Zip: "SW1A 1AA", Country: "gb"},
+			Telephone:           "+44 20 7946 0000",
+			OrganizationContact: Contact{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Telephone: "+44 20 7946 0000"},
+		})
+	}
+	return spec
+}
+
+func TestProvisionContainer(t *testing.T) {
+	api, c := newProvisionAPI(t)
+	api.reply("POST /container/1/children", 201, map[string]int{"id": 20})
+	api.reply("POST /organization", 201, map[string]int{"id": 30})
+
+	p, err := c.ProvisionContainer(provisionSpec("Example Ltd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&ProvisionedContainer{ID: 20, TemplateID: 5, OrganizationIDs: []int{30}}); !reflect.DeepEqual(p, want) {
+		t.Errorf("ProvisionContainer = %+v, want %+v", p, want)
+	}
+	var container NewContainerRequest
+	api.body("POST /container/1/children", &container)
+	if container.TemplateID != 5 || container.User.Username != "ada" || !reflect.DeepEqual(container.User.AccessRoles, []IDReference{{ID: 3}}) {
+		t.Errorf("The container request is %+v", container)
+	}
+	var org NewOrganizationRequest
+	api.body("POST /organization", &org)
+	if org.Container == nil || org.Container.ID != 20 {
+		t.Errorf("The organization was created in %+v", org.Container)
+	}
+}
+
+func TestProvisionContainerUnknownTemplateOrRole(t *testing.T) {
+	api, c := newProvisionAPI(t)
+
+	spec := provisionSpec()
+	spec.Template = "Region"
+	if _, err := c.ProvisionContainer(spec); err == nil || !strings.Contains(err.Error(), "template Region") {
+		t.Errorf("ProvisionContainer error = %v, want an unknown template", err)
+	}
+	spec = provisionSpec()
+	spec.Admin.Roles = []string{"Owner"}
+	if _, err := c.ProvisionContainer(spec); err == nil || !strings.Contains(err.Error(), "role Owner") {
+		t.Errorf("ProvisionContainer error = %v, want an unknown role", err)
+	}
+
+	// An error reading the template is returned as is, not as a missing role.
+	api.reply("GET /container/1/template/5", 200, map[string]interface{}{"errors": []map[string]string{{"code": "access_denied", "message": "Access denied."}}})
+	if _, err := c.ProvisionContainer(provisionSpec()); err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("ProvisionContainer error = %v, want the API error", err)
+	}
+	if n := api.count("POST /container/1/children"); n != 0 {
+		t.Errorf("The container was created %d times", n)
+	}
+}
+
+func TestProvisionContainerAdminRejected(t *testing.T) {
+	api, c := newProvisionAPI(t)
+	api.reply("POST /container/1/children", 400, map[string]interface{}{"errors": []map[string]string{{"code": "duplicate_username", "message": "Username is taken."}}})
+
+	p, err := c.ProvisionContainer(provisionSpec("Example Ltd"))
+	if p != nil || err == nil || !strings.Contains(err.Error(), "duplicate_username") {
+		t.Fatalf("ProvisionContainer = %+v, %v, want the admin user error", p, err)
+	}
+	// Nothing was created, so nothing is rolled back.
+	if n := api.count("POST /organization"); n != 0 {
+		t.Errorf("%d organizations were created", n)
+	}
+}
+
+func TestProvisionContainerRollback(t *testing.T) {
+	api, c := newProvisionAPI(t)
+	api.reply("POST /container/1/children", 201, map[string]int{"id": 20})
+	created := 0
+	api.handle("POST /organization", func(w http.ResponseWriter, r *http.Request) {
+		if created++; created == 2 {
+			writeJSON(w, 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_org", "message": "Invalid organization."}}})
+			return
+		}
+		writeJSON(w, 201, map[string]int{"id": 30})
+	})
+	api.reply("PUT /organization/30/deactivate", 204, nil)
+	api.reply("PUT /container/20/deactivate", 204, nil)
+
+	p, err := c.ProvisionContainer(provisionSpec("Example Ltd", "Example GmbH"))
+	if err == nil || !strings.Contains(err.Error(), "Example GmbH was not created") || strings.Contains(err.Error(), "rollback failed") {
+		t.Fatalf("ProvisionContainer error = %v, want a clean rollback", err)
+	}
+	if p == nil || p.ID != 20 || !reflect.DeepEqual(p.OrganizationIDs, []int{30}) {
+		t.Errorf("ProvisionContainer = %+v", p)
+	}
+	if api.count("PUT /organization/30/deactivate") != 1 || api.count("PUT /container/20/deactivate") != 1 {
+		t.Errorf("The rollback made %v", api.requests())
+	}
+}
+
+func TestProvisionContainerRollbackFails(t *testing.T) {
+	api, c := newProvisionAPI(t)
+	api.reply("POST /container/1/children", 201, map[string]int{"id": 20})
+	created := 0
+	api.handle("POST /organization", func(w http.ResponseWriter, r *http.Request) {
+		if created++; created == 2 {
+			writeJSON(w, 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_org", "message": "Invalid organization."}}})
+			return
+		}
+		writeJSON(w, 201, map[string]int{"id": 30})
+	})
+	api.reply("PUT /organization/30/deactivate", 400, map[string]interface{}{"errors": []map[string]string{{"code": "in_use", "message": "Organization in use."}}})
+	api.reply("PUT /container/20/deactivate", 204, nil)
+
+	_, err := c.ProvisionContainer(provisionSpec("Example Ltd", "Example GmbH"))
+	if err == nil || !strings.Contains(err.Error(), "rollback failed, the organization 30 was not deactivated: in_use") {
+		t.Fatalf("ProvisionContainer error = %v, want the failed rollback", err)
+	}
+}
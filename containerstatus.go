@@ -0,0 +1,106 @@
+package digicert
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// ContainerStatus presents whether a container and its children can be used.
+type ContainerStatus string
+
+// Container statuses accepted by SetContainerStatus.
+const (
+	ContainerActive   ContainerStatus = "active"
+	ContainerInactive ContainerStatus = "inactive"
+)
+
+// ErrContainerStatusDeclined is returned by SetContainerStatus when the confirmation callback declines the change.
+var ErrContainerStatusDeclined = errors.New("The container status change was declined")
+
+// ContainerImpact presents what is under a container, its children included, and so is affected when its status changes.
+type ContainerImpact struct {
+	Containers []*ContainerNode
+	Users      []ListedUser
+	Orders     []ListedOrder
+	Domains    []ListedDomain
+}
+
+// ContainerStatusOptions presents how SetContainerStatus previews the change.
+type ContainerStatusOptions struct {
+	ContainerTreeOptions
+	// Confirm is called with the impact of the change before it is made, the change is made only when it returns true.
+	Confirm func(*ContainerImpact) bool
+}
+
+// SetContainerStatus exports activates or deactivates the given container and all its children.
+// With a Confirm callback in options, the impact is computed by PreviewContainerImpact and returned, and the status is only changed once confirmed, otherwise ErrContainerStatusDeclined is returned.
+func (c *Client) SetContainerStatus(containerID string, status ContainerStatus, options *ContainerStatusOptions) (*ContainerImpact, error) {
+	var action string
+	switch status {
+	case ContainerActive:
+		action = "activate"
+	case ContainerInactive:
+		action = "deactivate"
+	default:
+		return nil, errors.New("The container status " + string(status) + " is not accepted")
+	}
+	var impact *ContainerImpact
+	if options != nil && options.Confirm != nil {
+		var err error
+		impact, err = c.PreviewContainerImpact(containerID, &options.ContainerTreeOptions)
+		if err != nil {
+			return nil, err
+		}
+		if !options.Confirm(impact) {
+			return impact, ErrContainerStatusDeclined
+		}
+	}
+	data, err := c.makeRequest("PUT", "/container/"+containerID+"/"+action, nil)
+	if err != nil {
+		return impact, err
+	}
+	if c.statusCode == 204 {
+		return impact, nil
+	}
+	var res SchemeValidationErrors
+	if err := json.Unmarshal(data, &res); err == nil {
+		if err := res.err(); err != nil {
+			return impact, err
+		}
+	}
+	return impact, errors.New("The container status was not changed, status " + strconv.Itoa(c.statusCode))
+}
+
+// PreviewContainerImpact exports lists the containers, users, orders and domains under containerID, recursively, e.g. to review what a deactivation would affect.
+func (c *Client) PreviewContainerImpact(containerID string, options *ContainerTreeOptions) (*ContainerImpact, error) {
+	tree, err := c.LoadContainerTree(containerID, options)
+	if err != nil {
+		return nil, err
+	}
+	impact := &ContainerImpact{Containers: tree.Nodes()}
+	walk := &ContainerWalkOptions{Users: true, Domains: true}
+	if options != nil {
+		walk.ContainerTreeOptions = *options
+	}
+	err = tree.Walk(walk, func(v *ContainerVisit) error {
+		impact.Users = append(impact.Users, v.Users...)
+		impact.Domains = append(impact.Domains, v.Domains...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = c.clone().eachOrderPage(func(page *ListOrders) bool {
+		for _, o := range page.Orders {
+			if tree.Find(o.Container.ID) != nil {
+				impact.Orders = append(impact.Orders, o)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
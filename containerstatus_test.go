@@ -0,0 +1,128 @@
+package digicert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newContainerStatusAPI answers the calls of newContainerTreeAPI and lists users, domains and orders in Corp (1), EMEA (2), its child Web (3) and a container outside the tree.
+func newContainerStatusAPI(t *testing.T) (*fakeAPI, *Client) {
+	api, c := newContainerTreeAPI(t)
+	api.reply("GET /user", 200, map[string]interface{}{"users": []map[string]interface{}{
+		{"id": 10, "username": "emea", "container": map[string]int{"id": 2}},
+		{"id": 11, "username": "web", "container": map[string]int{"id": 3}},
+		{"id": 12, "username": "root", "container": map[string]int{"id": 1}},
+	}})
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]interface{}{
+		{"id": 20, "name": "example.com", "container": map[string]int{"id": 2}},
+		{"id": 21, "name": "web.example.com", "container": map[string]int{"id": 3}},
+	}})
+	api.reply("GET /order/certificate/", 200, map[string]interface{}{
+		"orders": []map[string]interface{}{
+			{"id": 30, "container": map[string]int{"id": 3}},
+			{"id": 31, "container": map[string]int{"id": 99}},
+			{"id": 32, "container": map[string]int{"id": 2}},
+		},
+		"page": map[string]int{"total": 3},
+	})
+	return api, c
+}
+
+func TestSetContainerStatus(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /container/2/activate", 204, nil)
+	api.reply("PUT /container/2/deactivate", 204, nil)
+
+	if _, err := c.SetContainerStatus("2", ContainerActive, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SetContainerStatus("2", ContainerInactive, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.ActiveContainer("2"); !ok || err != nil {
+		t.Errorf("ActiveContainer = %v, %v", ok, err)
+	}
+	if ok, err := c.DeactiveContainer("2"); !ok || err != nil {
+		t.Errorf("DeactiveContainer = %v, %v", ok, err)
+	}
+	if got, want := api.requests(), []string{"PUT /container/2/activate", "PUT /container/2/deactivate", "PUT /container/2/activate", "PUT /container/2/deactivate"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+	if _, err := c.SetContainerStatus("2", ContainerStatus("Active"), nil); err == nil {
+		t.Error("SetContainerStatus accepted the status Active")
+	}
+}
+
+func TestSetContainerStatusError(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /container/2/deactivate", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_container", "message": "Invalid container."}}})
+	api.reply("PUT /container/2/activate", 200, map[string]interface{}{})
+
+	if ok, err := c.DeactiveContainer("2"); ok || err == nil || !strings.Contains(err.Error(), "invalid_container") {
+		t.Errorf("DeactiveContainer = %v, %v, want the API error", ok, err)
+	}
+	if ok, err := c.ActiveContainer("2"); ok || err == nil || !strings.Contains(err.Error(), "status 200") {
+		t.Errorf("ActiveContainer = %v, %v, want an unexpected status", ok, err)
+	}
+}
+
+func TestSetContainerStatusConfirm(t *testing.T) {
+	api, c := newContainerStatusAPI(t)
+	api.reply("PUT /container/1/deactivate", 204, nil)
+
+	var previewed *ContainerImpact
+	options := &ContainerStatusOptions{ContainerTreeOptions: *noWait, Confirm: func(impact *ContainerImpact) bool {
+		previewed = impact
+		return false
+	}}
+	impact, err := c.SetContainerStatus("1", ContainerInactive, options)
+	if err != ErrContainerStatusDeclined || impact == nil || impact != previewed {
+		t.Fatalf("SetContainerStatus = %+v, %v, want the impact and ErrContainerStatusDeclined", impact, err)
+	}
+	if n := api.count("PUT /container/1/deactivate"); n != 0 {
+		t.Fatalf("The container was deactivated %d times after the change was declined", n)
+	}
+
+	options.Confirm = func(*ContainerImpact) bool { return true }
+	if _, err := c.SetContainerStatus("1", ContainerInactive, options); err != nil {
+		t.Fatal(err)
+	}
+	if n := api.count("PUT /container/1/deactivate"); n != 1 {
+		t.Errorf("The container was deactivated %d times after the change was confirmed", n)
+	}
+}
+
+func TestPreviewContainerImpact(t *testing.T) {
+	_, c := newContainerStatusAPI(t)
+	impact, err := c.PreviewContainerImpact("1", noWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var containers, users, domains, orders []int
+	for _, n := range impact.Containers {
+		containers = append(containers, n.ID)
+	}
+	for _, u := range impact.Users {
+		users = append(users, u.ID)
+	}
+	for _, d := range impact.Domains {
+		domains = append(domains, d.ID)
+	}
+	for _, o := range impact.Orders {
+		orders = append(orders, o.ID)
+	}
+	if !reflect.DeepEqual(containers, []int{1, 2, 3, 4}) {
+		t.Errorf("Containers = %v", containers)
+	}
+	// The users, domains and orders of the children are part of the impact, those outside the tree are not.
+	if !reflect.DeepEqual(users, []int{12, 10, 11}) {
+		t.Errorf("Users = %v", users)
+	}
+	if !reflect.DeepEqual(domains, []int{20, 21}) {
+		t.Errorf("Domains = %v", domains)
+	}
+	if !reflect.DeepEqual(orders, []int{30, 32}) {
+		t.Errorf("Orders = %v", orders)
+	}
+}
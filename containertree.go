@@ -0,0 +1,254 @@
+package digicert
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContainerNode presents a container of a ContainerTree. Only the ID and Name of the root are known, as ViewContainer returns no more.
+type ContainerNode struct {
+	ID                 int
+	PublicID           string
+	Name               string
+	Description        string
+	ParentID           int
+	TemplateID         int
+	IsActive           bool
+	AllowedDomainNames []string
+
+	Parent   *ContainerNode
+	Children []*ContainerNode
+}
+
+// Path returns the names from the root of the tree down to n, separated by slashes, e.g. Corp/EMEA/Web.
+func (n *ContainerNode) Path() string {
+	if n.Parent == nil {
+		return n.Name
+	}
+	return n.Parent.Path() + "/" + n.Name
+}
+
+// Depth returns the number of ancestors of n in the tree.
+func (n *ContainerNode) Depth() int {
+	depth := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		depth++
+	}
+	return depth
+}
+
+// ContainerTree presents a container and all its descendants, as loaded by LoadContainerTree.
+type ContainerTree struct {
+	Root *ContainerNode
+	// Cycles lists the containers listed as children more than once, which were kept under their first parent only.
+	Cycles []int
+
+	client *Client
+	nodes  map[int]*ContainerNode
+}
+
+// ContainerTreeOptions presents how the tree is fetched and walked.
+type ContainerTreeOptions struct {
+	RateLimit
+}
+
+// LoadContainerTree exports walks the children of rootID recursively, fetching the containers of each level concurrently, and returns them as a tree.
+// A container met twice, e.g. through a cycle, is only added once.
+func (c *Client) LoadContainerTree(rootID string, options *ContainerTreeOptions) (*ContainerTree, error) {
+	concurrency, interval := containerTreeLimits(options)
+	root, err := c.clone().ViewContainer(rootID)
+	if err != nil {
+		return nil, err
+	}
+	if err := root.err(); err != nil {
+		return nil, err
+	}
+	tree := &ContainerTree{
+		Root:   &ContainerNode{ID: root.ID, Name: root.Name},
+		client: c.clone(),
+		nodes:  make(map[int]*ContainerNode),
+	}
+	tree.nodes[root.ID] = tree.Root
+
+	level := []*ContainerNode{tree.Root}
+	for len(level) > 0 {
+		children := make([]*ListChilContainersResponse, len(level))
+		errs := make([]error, len(level))
+		throttle(len(level), concurrency, interval, func(i int) {
+			res, err := c.clone().ListChilContainers(strconv.Itoa(level[i].ID))
+			if err == nil {
+				err = res.err()
+			}
+			children[i], errs[i] = res, err
+		})
+		var next []*ContainerNode
+		for i, parent := range level {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			for _, child := range children[i].Containers {
+				if tree.nodes[child.ID] != nil {
+					tree.Cycles = append(tree.Cycles, child.ID)
+					continue
+				}
+				node := &ContainerNode{
+					ID:                 child.ID,
+					PublicID:           child.PublicID,
+					Name:               child.Name,
+					Description:        child.Description,
+					ParentID:           child.ParentID,
+					TemplateID:         child.TemplateID,
+					IsActive:           child.IsActive,
+					AllowedDomainNames: child.AllowedDomainNames,
+					Parent:             parent,
+				}
+				parent.Children = append(parent.Children, node)
+				tree.nodes[child.ID] = node
+				next = append(next, node)
+			}
+		}
+		level = next
+	}
+	return tree, nil
+}
+
+// Find returns the container with the given ID, nil when it is not in the tree.
+func (t *ContainerTree) Find(id int) *ContainerNode {
+	return t.nodes[id]
+}
+
+// Lookup returns the container at path, the slash separated names from the root, e.g. Corp/EMEA/Web. Names are compared case-insensitively and nil is returned when there is no such container.
+func (t *ContainerTree) Lookup(path string) *ContainerNode {
+	names := strings.Split(strings.Trim(path, "/"), "/")
+	if !strings.EqualFold(strings.TrimSpace(names[0]), t.Root.Name) {
+		return nil
+	}
+	node := t.Root
+next:
+	for _, name := range names[1:] {
+		name = strings.TrimSpace(name)
+		for _, child := range node.Children {
+			if strings.EqualFold(child.Name, name) {
+				node = child
+				continue next
+			}
+		}
+		return nil
+	}
+	return node
+}
+
+// Nodes returns the containers of the tree, each parent before its children.
+func (t *ContainerTree) Nodes() []*ContainerNode {
+	var nodes []*ContainerNode
+	var add func(n *ContainerNode)
+	add = func(n *ContainerNode) {
+		nodes = append(nodes, n)
+		for _, child := range n.Children {
+			add(child)
+		}
+	}
+	add(t.Root)
+	return nodes
+}
+
+// ContainerVisit presents a container passed to the Walk visitor, with the data requested by the ContainerWalkOptions.
+type ContainerVisit struct {
+	Node          *ContainerNode
+	Users         []ListedUser
+	Domains       []ListedDomain
+	Organizations []ListedOrganization
+}
+
+// ContainerWalkOptions presents what Walk pulls for every container.
+type ContainerWalkOptions struct {
+	ContainerTreeOptions
+	Users         bool
+	Domains       bool
+	Organizations bool
+}
+
+// Walk calls visit for every container of the tree, each parent before its children, e.g. for reporting.
+// The users, domains and organizations requested by options are fetched concurrently before the first visit and only those directly in the container are passed. Walk stops at the first error returned by visit.
+func (t *ContainerTree) Walk(options *ContainerWalkOptions, visit func(*ContainerVisit) error) error {
+	if options == nil {
+		options = new(ContainerWalkOptions)
+	}
+	nodes := t.Nodes()
+	visits := make(map[int]*ContainerVisit, len(nodes))
+	for _, n := range nodes {
+		visits[n.ID] = &ContainerVisit{Node: n}
+	}
+	rootID := strconv.Itoa(t.Root.ID)
+
+	if options.Users {
+		// Users are listed for the root container with all its children.
+		users, err := t.client.clone().ListUsers(rootID)
+		if err != nil {
+			return err
+		}
+		if err := users.err(); err != nil {
+			return err
+		}
+		for _, u := range users.Users {
+			if v, ok := visits[u.Container.ID]; ok {
+				v.Users = append(v.Users, u)
+			}
+		}
+	}
+	if options.Organizations {
+		orgs, err := t.client.clone().ListAllOrganizations()
+		if err != nil {
+			return err
+		}
+		if err := orgs.err(); err != nil {
+			return err
+		}
+		for _, org := range orgs.Organizations {
+			if v, ok := visits[org.Container.ID]; ok {
+				v.Organizations = append(v.Organizations, org)
+			}
+		}
+	}
+	if options.Domains {
+		concurrency, interval := containerTreeLimits(&options.ContainerTreeOptions)
+		errs := make([]error, len(nodes))
+		throttle(len(nodes), concurrency, interval, func(i int) {
+			list, err := t.client.clone().ListDomains(strconv.Itoa(nodes[i].ID))
+			if err == nil {
+				err = list.err()
+			}
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			var domains []ListedDomain
+			for _, d := range list.Domains {
+				if d.Container.ID == nodes[i].ID || d.Container.ID == 0 {
+					domains = append(domains, d)
+				}
+			}
+			visits[nodes[i].ID].Domains = domains
+		})
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		if err := visit(visits[n.ID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containerTreeLimits(options *ContainerTreeOptions) (int, time.Duration) {
+	if options == nil {
+		return RateLimit{}.limits()
+	}
+	return options.limits()
+}
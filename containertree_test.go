@@ -0,0 +1,149 @@
+package digicert
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// newContainerTreeAPI answers the container calls of the tree Corp (1) with the children EMEA (2) and US (4), and Web (3) under EMEA.
+// Web lists Corp again and US lists EMEA again, which must not be walked twice.
+func newContainerTreeAPI(t *testing.T) (*fakeAPI, *Client) {
+	api, c := newFakeAPI(t)
+	children := func(containers ...map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"containers": containers}
+	}
+	api.reply("GET /container/1", 200, map[string]interface{}{"id": 1, "name": "Corp"})
+	api.reply("GET /container/1/children", 200, children(
+		map[string]interface{}{"id": 2, "name": "EMEA", "parent_id": 1},
+		map[string]interface{}{"id": 4, "name": "US", "parent_id": 1},
+	))
+	api.reply("GET /container/2/children", 200, children(map[string]interface{}{"id": 3, "name": "Web", "parent_id": 2}))
+	api.reply("GET /container/3/children", 200, children(map[string]interface{}{"id": 1, "name": "Corp"}))
+	api.reply("GET /container/4/children", 200, children(map[string]interface{}{"id": 2, "name": "EMEA", "parent_id": 1}))
+	return api, c
+}
+
+// noWait loads and walks trees without waiting between the calls.
+var noWait = &ContainerTreeOptions{RateLimit{Interval: -1}}
+
+func TestLoadContainerTree(t *testing.T) {
+	api, c := newContainerTreeAPI(t)
+	tree, err := c.LoadContainerTree("1", noWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, n := range tree.Nodes() {
+		paths = append(paths, n.Path())
+	}
+	if want := []string{"Corp", "Corp/EMEA", "Corp/EMEA/Web", "Corp/US"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Nodes = %v, want %v", paths, want)
+	}
+	cycles := append([]int(nil), tree.Cycles...)
+	sort.Ints(cycles)
+	if !reflect.DeepEqual(cycles, []int{1, 2}) {
+		t.Errorf("Cycles = %v, want [1 2]", tree.Cycles)
+	}
+	for _, id := range []string{"1", "2", "3", "4"} {
+		if n := api.count("GET /container/" + id + "/children"); n != 1 {
+			t.Errorf("The children of %s were listed %d times", id, n)
+		}
+	}
+	if web := tree.Find(3); web == nil || web.Depth() != 2 || web.Parent.ID != 2 {
+		t.Errorf("Find(3) = %+v", web)
+	}
+}
+
+func TestContainerTreeLookup(t *testing.T) {
+	_, c := newContainerTreeAPI(t)
+	tree, err := c.LoadContainerTree("1", noWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"Corp", 1},
+		{"Corp/EMEA/Web", 3},
+		{"corp/emea/WEB", 3},
+		{"/Corp/US/", 4},
+		{"Corp / EMEA", 2},
+		{"Corp/Web", 0},
+		{"Corp/EMEA/Web/Shop", 0},
+		{"EMEA", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		n := tree.Lookup(tt.path)
+		if tt.want == 0 && n != nil || tt.want != 0 && (n == nil || n.ID != tt.want) {
+			t.Errorf("Lookup(%q) = %+v, want %d", tt.path, n, tt.want)
+		}
+	}
+}
+
+func TestContainerTreeWalk(t *testing.T) {
+	api, c := newContainerTreeAPI(t)
+	api.reply("GET /user", 200, map[string]interface{}{"users": []map[string]interface{}{
+		{"id": 10, "username": "root", "container": map[string]int{"id": 1}},
+		{"id": 11, "username": "web", "container": map[string]int{"id": 3}},
+		{"id": 12, "username": "other", "container": map[string]int{"id": 99}},
+	}})
+	api.handle("GET /domain", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.URL.Query().Get("container_id"))
+		domains := []map[string]interface{}{{"id": 20 + id, "name": strconv.Itoa(id) + ".example.com", "container": map[string]int{"id": id}}}
+		if id == 2 {
+			// The listing of a parent includes the domains of its children.
+			domains = append(domains, map[string]interface{}{"id": 30, "name": "web.example.com", "container": map[string]int{"id": 3}})
+		}
+		writeJSON(w, 200, map[string]interface{}{"domains": domains})
+	})
+	api.reply("GET /organization", 200, map[string]interface{}{"organizations": []map[string]interface{}{
+		{"id": 30, "name": "Example Ltd", "container": map[string]int{"id": 2}},
+	}})
+
+	got := make(map[int][3]int)
+	err := loadTree(t, c).Walk(&ContainerWalkOptions{ContainerTreeOptions: *noWait, Users: true, Domains: true, Organizations: true}, func(v *ContainerVisit) error {
+		got[v.Node.ID] = [3]int{len(v.Users), len(v.Domains), len(v.Organizations)}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int][3]int{1: {1, 1, 0}, 2: {0, 1, 1}, 3: {1, 1, 0}, 4: {0, 1, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("users, domains and organizations per container = %v, want %v", got, want)
+	}
+	if n := api.count("GET /user"); n != 1 {
+		t.Errorf("The users were listed %d times", n)
+	}
+}
+
+func TestContainerTreeWalkStops(t *testing.T) {
+	_, c := newContainerTreeAPI(t)
+	stop := errors.New("stop")
+	var visited []int
+	err := loadTree(t, c).Walk(&ContainerWalkOptions{ContainerTreeOptions: *noWait}, func(v *ContainerVisit) error {
+		visited = append(visited, v.Node.ID)
+		if v.Node.ID == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || !reflect.DeepEqual(visited, []int{1, 2}) {
+		t.Errorf("Walk = %v after visiting %v, want stop after [1 2]", err, visited)
+	}
+}
+
+// loadTree loads the tree of container 1.
+func loadTree(t *testing.T, c *Client) *ContainerTree {
+	tree, err := c.LoadContainerTree("1", noWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
@@ -0,0 +1,114 @@
+package digicert
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SANCoverage presents a DNS name of a request and the domain covering it.
+type SANCoverage struct {
+	Name string
+	// DomainID and DomainName are zero when no domain of the organization covers the name.
+	DomainID       int
+	DomainName     string
+	ValidatedUntil time.Time
+}
+
+// SANCoverageReport presents which names of a request can be issued without further DCV.
+type SANCoverageReport struct {
+	Covered []SANCoverage
+	// NeedsDCV lists the names without a domain, or whose domain is inactive or has no completed validation for the validation type of the product.
+	NeedsDCV []SANCoverage
+	// Lapsing lists the covered names whose validation ends within the requested window.
+	Lapsing []SANCoverage
+}
+
+// CheckSANCoverage exports Use this before submitting an OV or EV order of product to know whether every DNS name is covered by an active domain of the organization in the container, validated for the validation type of the product.
+// A name is covered by the most specific domain equal to it or one of its parents, wildcards are matched on their base name, and only validations whose DCV is complete count. Covered names validated for less than lapseWithin are also reported as lapsing.
+func (c *Client) CheckSANCoverage(names []string, orgID, containerID string, product ProductNameID, lapseWithin time.Duration) (*SANCoverageReport, error) {
+	validationType := product.ValidationType()
+	switch validationType {
+	case "ov", "ev":
+	default:
+		return nil, errors.New("The product " + product.String() + " is not validated through the organization domains")
+	}
+	list, err := c.ListDomains(containerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := list.err(); err != nil {
+		return nil, err
+	}
+	candidates := make(map[string]int)
+	for _, d := range list.Domains {
+		if strconv.Itoa(d.Organization.ID) == orgID {
+			candidates[strings.ToLower(d.Name)] = d.ID
+		}
+	}
+
+	now := time.Now()
+	report := new(SANCoverageReport)
+	details := make(map[int]*ViewADomainResponse)
+	for _, name := range names {
+		entry := SANCoverage{Name: name}
+		entry.DomainName, entry.DomainID = coveringDomain(candidates, name)
+		if entry.DomainID == 0 {
+			report.NeedsDCV = append(report.NeedsDCV, entry)
+			continue
+		}
+		domain, ok := details[entry.DomainID]
+		if !ok {
+			if domain, err = c.ViewADomain(strconv.Itoa(entry.DomainID)); err != nil {
+				return nil, err
+			}
+			if err := domain.err(); err != nil {
+				return nil, err
+			}
+			details[entry.DomainID] = domain
+		}
+		entry.ValidatedUntil = validatedUntil(domain, validationType)
+		switch {
+		case !domain.IsActive || !entry.ValidatedUntil.After(now):
+			report.NeedsDCV = append(report.NeedsDCV, entry)
+		case entry.ValidatedUntil.Before(now.Add(lapseWithin)):
+			report.Covered = append(report.Covered, entry)
+			report.Lapsing = append(report.Lapsing, entry)
+		default:
+			report.Covered = append(report.Covered, entry)
+		}
+	}
+	return report, nil
+}
+
+// coveringDomain returns the longest domain of candidates equal to name or one of its parents.
+func coveringDomain(candidates map[string]int, name string) (string, int) {
+	for zone := normalizeHost(name); zone != ""; {
+		if id, ok := candidates[zone]; ok {
+			return zone, id
+		}
+		i := strings.Index(zone, ".")
+		if i < 0 {
+			break
+		}
+		zone = zone[i+1:]
+	}
+	return "", 0
+}
+
+// validatedUntil returns the end of the active validation usable for validationType, an EV validation also covers OV. A validation whose DCV is pending or expired is not usable.
+func validatedUntil(domain *ViewADomainResponse, validationType string) time.Time {
+	var until time.Time
+	for _, v := range domain.Validations {
+		if v.Status != "active" || v.DcvStatus != DCVStatusComplete {
+			continue
+		}
+		if v.Type == validationType || (validationType == "ov" && v.Type == "ev") {
+			if v.ValidatedUntil.After(until) {
+				until = v.ValidatedUntil.Time
+			}
+		}
+	}
+	return until
+}
@@ -0,0 +1,76 @@
+package digicert
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckSANCoverage(t *testing.T) {
+	api, c := newFakeAPI(t)
+	day := func(d time.Duration) string { return time.Now().Add(d).Format("2006-01-02") }
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]interface{}{
+		{"id": 1, "name": "example.com", "organization": map[string]int{"id": 3}},
+		{"id": 2, "name": "shop.example.com", "organization": map[string]int{"id": 3}},
+		{"id": 4, "name": "example.net", "organization": map[string]int{"id": 3}},
+		{"id": 5, "name": "example.org", "organization": map[string]int{"id": 3}},
+		// The domain of another organization covers nothing.
+		{"id": 6, "name": "example.io", "organization": map[string]int{"id": 9}},
+	}})
+	validation := func(kind, until, dcv string) map[string]string {
+		return map[string]string{"type": kind, "status": "active", "validated_until": until, "dcv_status": dcv}
+	}
+	api.reply("GET /domain/1", 200, map[string]interface{}{"id": 1, "name": "example.com", "is_active": true, "validations": []interface{}{validation("ev", day(400*24*time.Hour), "complete")}})
+	api.reply("GET /domain/2", 200, map[string]interface{}{"id": 2, "name": "shop.example.com", "is_active": true, "validations": []interface{}{validation("ov", day(10*24*time.Hour), "complete")}})
+	api.reply("GET /domain/4", 200, map[string]interface{}{"id": 4, "name": "example.net", "is_active": true, "validations": []interface{}{validation("ov", day(400*24*time.Hour), "pending")}})
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.org", "is_active": true, "validations": []interface{}{validation("ov", day(400*24*time.Hour), "expired")}})
+
+	names := []string{"www.example.com", "*.example.com", "api.shop.example.com", "example.net", "www.example.org", "example.io", "example.dev"}
+	report, err := c.CheckSANCoverage(names, "3", "1", ProductSSLPlus, 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := func(entries []SANCoverage) string {
+		var s []string
+		for _, e := range entries {
+			s = append(s, e.Name)
+		}
+		return strings.Join(s, " ")
+	}
+	if want := "www.example.com *.example.com api.shop.example.com"; got(report.Covered) != want {
+		t.Errorf("Covered = %s, want %s", got(report.Covered), want)
+	}
+	if want := "api.shop.example.com"; got(report.Lapsing) != want {
+		t.Errorf("Lapsing = %s, want %s", got(report.Lapsing), want)
+	}
+	if want := "example.net www.example.org example.io example.dev"; got(report.NeedsDCV) != want {
+		t.Errorf("NeedsDCV = %s, want %s", got(report.NeedsDCV), want)
+	}
+	if report.Covered[2].DomainID != 2 {
+		t.Errorf("api.shop.example.com is covered by %+v, want the most specific domain", report.Covered[2])
+	}
+	if n := api.count("GET /domain/1"); n != 1 {
+		t.Errorf("The domain was viewed %d times", n)
+	}
+
+	// An EV product needs an EV validation, the OV one of shop.example.com does not count.
+	report, err = c.CheckSANCoverage([]string{"api.shop.example.com"}, "3", "1", ProductSSLEVPlus, 0)
+	if err != nil || len(report.NeedsDCV) != 1 {
+		t.Errorf("CheckSANCoverage(ev) = %+v, %v", report, err)
+	}
+}
+
+func TestCheckSANCoverageErrors(t *testing.T) {
+	api, c := newFakeAPI(t)
+	if _, err := c.CheckSANCoverage([]string{"example.com"}, "3", "1", ProductSSLDVRapidSSL, 0); err == nil {
+		t.Error("A DV product is accepted")
+	}
+	if _, err := c.CheckSANCoverage([]string{"example.com"}, "3", "1", "ov", 0); err == nil {
+		t.Error("A raw validation type is accepted as a product")
+	}
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]interface{}{{"id": 1, "name": "example.com", "organization": map[string]int{"id": 3}}}})
+	api.reply("GET /domain/1", 200, map[string]interface{}{"errors": []map[string]string{{"code": "access_denied", "message": "Permission denied."}}})
+	if _, err := c.CheckSANCoverage([]string{"example.com"}, "3", "1", ProductSSLPlus, 0); err == nil {
+		t.Error("The domain error is ignored")
+	}
+}
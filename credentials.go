@@ -0,0 +1,152 @@
+package digicert
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies the API key, it is consulted on every request so a rotated key is used as soon as the provider returns it.
+type CredentialProvider interface {
+	APIKey() (string, error)
+}
+
+// redacted replaces the API key in errors and debug output.
+const redacted = "[REDACTED]"
+
+// StaticCredentials exports a fixed API key.
+type StaticCredentials string
+
+// APIKey returns the key.
+func (s StaticCredentials) APIKey() (string, error) {
+	if s == "" {
+		return "", errors.New("The digicert api credentials must input")
+	}
+	return string(s), nil
+}
+
+// String hides the key.
+func (s StaticCredentials) String() string {
+	return redacted
+}
+
+// GoString hides the key.
+func (s StaticCredentials) GoString() string {
+	return redacted
+}
+
+// EnvCredentials exports the name of an environment variable holding the API key, read on every request.
+type EnvCredentials string
+
+// APIKey returns the value of the variable.
+func (e EnvCredentials) APIKey() (string, error) {
+	key := strings.TrimSpace(os.Getenv(string(e)))
+	if key == "" {
+		return "", errors.New("The environment variable " + string(e) + " holds no api key")
+	}
+	return key, nil
+}
+
+// FileCredentials exports an API key read from a file, reloaded when the file changes so a rotated key is picked up without restart.
+// It is safe for concurrent use.
+type FileCredentials struct {
+	path string
+	// MinInterval is the minimum time between two checks of the file, defaults to one second.
+	MinInterval time.Duration
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+	checked time.Time
+}
+
+// NewFileCredentials exports reads the API key from path, surrounding spaces are trimmed.
+func NewFileCredentials(path string) (*FileCredentials, error) {
+	f := &FileCredentials{path: path}
+	if _, err := f.APIKey(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// APIKey returns the key, reading the file again when its size or modification time changed.
+func (f *FileCredentials) APIKey() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	interval := f.MinInterval
+	if interval == 0 {
+		interval = time.Second
+	}
+	if f.key != "" && time.Since(f.checked) < interval {
+		return f.key, nil
+	}
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	}
+	f.checked = time.Now()
+	if f.key != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.key, nil
+	}
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", errors.New("The file " + f.path + " holds no api key")
+	}
+	f.key, f.modTime, f.size = key, info.ModTime(), info.Size()
+	return f.key, nil
+}
+
+// String hides the key.
+func (f *FileCredentials) String() string {
+	return "FileCredentials(" + f.path + ")"
+}
+
+// GoString hides the key.
+func (f *FileCredentials) GoString() string {
+	return f.String()
+}
+
+// ChainCredentials exports providers tried in order, the first key returned is used.
+type ChainCredentials []CredentialProvider
+
+// APIKey returns the key of the first provider returning one, else the error of the last provider that failed.
+func (p ChainCredentials) APIKey() (string, error) {
+	err := errors.New("There is no credential provider in the chain")
+	if len(p) > 0 {
+		err = errors.New("No credential provider in the chain returned an api key")
+	}
+	for _, provider := range p {
+		key, perr := provider.APIKey()
+		if perr == nil && key != "" {
+			return key, nil
+		}
+		if perr != nil {
+			err = perr
+		}
+	}
+	return "", err
+}
+
+// redact removes key from s.
+func redact(s, key string) string {
+	if key == "" {
+		return s
+	}
+	return strings.Replace(s, key, redacted, -1)
+}
+
+// redactError returns err with key removed from its message.
+func redactError(err error, key string) error {
+	if err == nil || key == "" || !strings.Contains(err.Error(), key) {
+		return err
+	}
+	return errors.New(redact(err.Error(), key))
+}
@@ -0,0 +1,174 @@
+package digicert
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// credentialsFunc adapts a function to CredentialProvider.
+type credentialsFunc func() (string, error)
+
+func (f credentialsFunc) APIKey() (string, error) {
+	return f()
+}
+
+func TestChainCredentials(t *testing.T) {
+	empty := credentialsFunc(func() (string, error) { return "", nil })
+	failing := credentialsFunc(func() (string, error) { return "", errors.New("vault sealed") })
+
+	if key, err := (ChainCredentials{failing, empty, StaticCredentials("key")}).APIKey(); err != nil || key != "key" {
+		t.Errorf("APIKey = %q, %v", key, err)
+	}
+	for _, chain := range []ChainCredentials{nil, {empty}, {empty, empty}} {
+		if key, err := chain.APIKey(); err == nil {
+			t.Errorf("%d providers returned no key, APIKey = %q, nil", len(chain), key)
+		}
+	}
+	if _, err := (ChainCredentials{failing, empty}).APIKey(); err == nil || err.Error() != "vault sealed" {
+		t.Errorf("APIKey error = %v, want the error of the failing provider", err)
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	tests := []struct {
+		err  error
+		key  string
+		want string
+	}{
+		{errors.New("Get https://example.com/?key=secret: secret refused"), "secret", "Get https://example.com/?key=[REDACTED]: [REDACTED] refused"},
+		{errors.New("connection refused"), "secret", "connection refused"},
+		{errors.New("secret"), "", "secret"},
+	}
+	for _, tt := range tests {
+		if got := redactError(tt.err, tt.key); got.Error() != tt.want {
+			t.Errorf("redactError(%q, %q) = %q, want %q", tt.err, tt.key, got, tt.want)
+		}
+	}
+	if redactError(nil, "secret") != nil {
+		t.Error("redactError(nil) is not nil")
+	}
+}
+
+func TestResponseBodyRedacted(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /key/1", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_key", "message": "The key test-key is invalid."}}})
+	res, err := c.ViewAPIKey("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = res.err()
+	if err == nil || strings.Contains(err.Error(), "test-key") || !strings.Contains(err.Error(), redacted) {
+		t.Errorf("error = %v, want the key redacted", err)
+	}
+}
+
+func TestClientFormatHidesKey(t *testing.T) {
+	clients := []*Client{}
+	c, _ := New("secret-key")
+	clients = append(clients, c)
+	c, _ = NewWithCredentials(StaticCredentials("secret-key"))
+	clients = append(clients, c)
+	for _, c := range clients {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+			for _, v := range []interface{}{c, *c} {
+				if out := fmt.Sprintf(format, v); strings.Contains(out, "secret-key") {
+					t.Errorf("%s of the client shows the key: %s", format, out)
+				}
+			}
+		}
+	}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		if out := fmt.Sprintf(format, StaticCredentials("secret-key")); strings.Contains(out, "secret-key") {
+			t.Errorf("%s of StaticCredentials shows the key: %s", format, out)
+		}
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	api, _ := newFakeAPI(t)
+	api.acceptKey("env-key-1")
+	api.acceptKey("env-key-2")
+	seen := []string{}
+	api.handle("GET /key/1", func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("X-DC-DEVKEY"))
+		writeJSON(w, 200, map[string]int{"id": 1})
+	})
+	c, err := NewWithCredentials(EnvCredentials("DIGICERT_TEST_KEY"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DIGICERT_TEST_KEY", " env-key-1\n")
+	if _, err := c.ViewAPIKey("1"); err != nil {
+		t.Fatal(err)
+	}
+	// The variable is read again on every request.
+	t.Setenv("DIGICERT_TEST_KEY", "env-key-2")
+	if _, err := c.ViewAPIKey("1"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"env-key-1", "env-key-2"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("The keys sent are %v, want %v", seen, want)
+	}
+
+	t.Setenv("DIGICERT_TEST_KEY", "")
+	if _, err := c.ViewAPIKey("1"); err == nil || !strings.Contains(err.Error(), "DIGICERT_TEST_KEY") {
+		t.Errorf("ViewAPIKey error = %v, want the empty variable", err)
+	}
+	if len(seen) != 2 {
+		t.Error("A request was sent without a key")
+	}
+}
+
+func TestFileCredentialsReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := ioutil.WriteFile(path, []byte("file-key-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewFileCredentials(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.MinInterval = time.Nanosecond
+	if key, err := f.APIKey(); err != nil || key != "file-key-1" {
+		t.Fatalf("APIKey = %q, %v", key, err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("file-key-22"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if key, err := f.APIKey(); err != nil || key != "file-key-22" {
+		t.Errorf("APIKey after the rotation = %q, %v", key, err)
+	}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		if out := fmt.Sprintf(format, f); strings.Contains(out, "file-key") {
+			t.Errorf("%s of FileCredentials shows the key: %s", format, out)
+		}
+	}
+
+	// Within MinInterval the file is not checked again.
+	f.MinInterval = time.Hour
+	if err := ioutil.WriteFile(path, []byte("file-key-333"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if key, _ := f.APIKey(); key != "file-key-22" {
+		t.Errorf("APIKey within MinInterval = %q, want the cached key", key)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	ioutil.WriteFile(empty, []byte(" \n"), 0600)
+	if _, err := NewFileCredentials(empty); err == nil {
+		t.Error("NewFileCredentials accepts a file without a key")
+	}
+	if _, err := NewFileCredentials(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("NewFileCredentials accepts a missing file")
+	}
+}
@@ -0,0 +1,302 @@
+package digicert
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DNSProvider exports publishes and removes the TXT records used by the dns-txt-token DCV method.
+type DNSProvider interface {
+	Present(fqdn, value string) error
+	CleanUp(fqdn, value string) error
+}
+
+// DNSSolver exports completes the dns-txt-token Domain Control Validation (DCV) of an order or a domain.
+// It fetches the random value, publishes it through Provider, waits until every authoritative nameserver serves it, asks DigiCert to check and removes the records once DigiCert reports the DCV complete.
+type DNSSolver struct {
+	Client   *Client
+	Provider DNSProvider
+	// RecordPrefix is prepended to the domain name to build the record name, e.g. "_dnsauth". Empty publishes on the domain itself.
+	RecordPrefix string
+	// Nameservers overrides the authoritative nameservers lookup, entries are "host:port".
+	Nameservers []string
+	// PropagationTimeout defaults to 5 minutes and PollInterval to 10 seconds.
+	PropagationTimeout time.Duration
+	PollInterval       time.Duration
+	// CheckTimeout bounds how long DigiCert is asked again while the DCV is pending, defaults to 5 minutes.
+	CheckTimeout time.Duration
+}
+
+// SolveOrder exports validates all names of a pending DV order with the dns-txt-token method.
+// When the DCV is still pending after CheckTimeout, or the check fails, the records are left in place for DigiCert to finish and an error is returned along with the last check.
+func (s *DNSSolver) SolveOrder(ctx context.Context, orderID string) (*DVCheckDCVResponse, error) {
+	return s.challenge().order(ctx, s.Client, orderID)
+}
+
+// SolveDomain exports validates a domain with the dns-txt-token method.
+// When the DCV is still pending after CheckTimeout, or the check fails, the records are left in place for DigiCert to finish and an error is returned along with the last check.
+func (s *DNSSolver) SolveDomain(ctx context.Context, domainID string) (*ApproveStatuesResponse, error) {
+	return s.challenge().domain(ctx, s.Client, domainID)
+}
+
+func (s *DNSSolver) challenge() *dcvChallenge {
+	return &dcvChallenge{
+		method:   DCVDNSTXTToken,
+		publish:  s.publish,
+		interval: s.PollInterval,
+		timeout:  s.CheckTimeout,
+		kept:     "its TXT records are left in place",
+	}
+}
+
+// publish presents value on the record of every distinct name and waits for propagation, the returned cleanup removes what was presented.
+func (s *DNSSolver) publish(ctx context.Context, names []string, value string) (func(), error) {
+	if value == "" {
+		return func() {}, errors.New("DigiCert returned no random value")
+	}
+	var presented []string
+	cleanup := func() {
+		for _, fqdn := range presented {
+			s.Provider.CleanUp(fqdn, value)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		fqdn := s.recordName(name)
+		if name == "" || seen[fqdn] {
+			continue
+		}
+		seen[fqdn] = true
+		if err := s.Provider.Present(fqdn, value); err != nil {
+			return cleanup, err
+		}
+		presented = append(presented, fqdn)
+	}
+	for _, fqdn := range presented {
+		if err := s.waitPropagation(ctx, fqdn, value); err != nil {
+			return cleanup, err
+		}
+	}
+	return cleanup, nil
+}
+
+func (s *DNSSolver) recordName(name string) string {
+	name = normalizeHost(name)
+	if s.RecordPrefix != "" {
+		name = s.RecordPrefix + "." + name
+	}
+	return name
+}
+
+// waitPropagation polls the authoritative nameservers of fqdn until all of them serve value.
+func (s *DNSSolver) waitPropagation(ctx context.Context, fqdn, value string) error {
+	timeout, interval := s.PropagationTimeout, s.PollInterval
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+	servers := s.Nameservers
+	if len(servers) == 0 {
+		var err error
+		if servers, err = authoritativeNameservers(fqdn); err != nil {
+			return err
+		}
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if servesTXT(ctx, servers, fqdn, value) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("The TXT record of " + fqdn + " did not propagate to the authoritative nameservers")
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// authoritativeNameservers walks up the labels of fqdn until a zone with NS records is found.
+func authoritativeNameservers(fqdn string) ([]string, error) {
+	for zone := fqdn; strings.Contains(zone, "."); zone = zone[strings.Index(zone, ".")+1:] {
+		ns, err := net.LookupNS(zone)
+		if err != nil || len(ns) == 0 {
+			continue
+		}
+		var servers []string
+		for _, n := range ns {
+			servers = append(servers, net.JoinHostPort(strings.TrimSuffix(n.Host, "."), "53"))
+		}
+		return servers, nil
+	}
+	return nil, errors.New("There is no authoritative nameserver for " + fqdn)
+}
+
+// servesTXT reports whether every server answers value among the TXT records of fqdn.
+func servesTXT(ctx context.Context, servers []string, fqdn, value string) bool {
+	for _, server := range servers {
+		server := server
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		records, err := resolver.LookupTXT(ctx, fqdn)
+		cancel()
+		if err != nil || !contains(records, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryDNSProvider exports a DNSProvider keeping the records in memory, for tests and for serving them with ServeMemoryDNS.
+type MemoryDNSProvider struct {
+	mu      sync.Mutex
+	records map[string][]string
+}
+
+// Present adds value to the TXT records of fqdn.
+func (p *MemoryDNSProvider) Present(fqdn, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.records == nil {
+		p.records = make(map[string][]string)
+	}
+	if !contains(p.records[fqdn], value) {
+		p.records[fqdn] = append(p.records[fqdn], value)
+	}
+	return nil
+}
+
+// CleanUp removes value from the TXT records of fqdn.
+func (p *MemoryDNSProvider) CleanUp(fqdn, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var kept []string
+	for _, v := range p.records[fqdn] {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == 0 {
+		delete(p.records, fqdn)
+	} else {
+		p.records[fqdn] = kept
+	}
+	return nil
+}
+
+// TXT returns the TXT records of fqdn.
+func (p *MemoryDNSProvider) TXT(fqdn string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.records[fqdn]...)
+}
+
+// ServeMemoryDNS exports starts an authoritative DNS server on the UDP addr (usually ":53") answering the TXT records of provider, e.g. as the nameserver of a delegated _dnsauth zone or as DNSSolver.Nameservers in tests.
+// Close the returned connection once validation is done.
+func ServeMemoryDNS(addr string, provider *MemoryDNSProvider) (net.PacketConn, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, peer, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if answer := provider.answer(buf[:n]); answer != nil {
+				conn.WriteTo(answer, peer)
+			}
+		}
+	}()
+	return conn, nil
+}
+
+// DNS message constants of RFC 1035.
+const (
+	dnsTypeTXT  = 16
+	dnsClassIN  = 1
+	dnsRcodeNXD = 3
+	dnsRcodeNI  = 4
+)
+
+// answer builds the response to a DNS query, nil when the query is malformed.
+// Names without records answer NXDOMAIN and anything but an IN TXT query answers not implemented.
+func (p *MemoryDNSProvider) answer(query []byte) []byte {
+	if len(query) < 12 || query[2]&0x80 != 0 || query[4] != 0 || query[5] != 1 {
+		return nil
+	}
+	var labels []string
+	i := 12
+	for {
+		if i >= len(query) {
+			return nil
+		}
+		size := int(query[i])
+		i++
+		if size == 0 {
+			break
+		}
+		if size > 63 || i+size > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[i:i+size]))
+		i += size
+	}
+	if i+4 > len(query) {
+		return nil
+	}
+	qtype, qclass := int(query[i])<<8|int(query[i+1]), int(query[i+2])<<8|int(query[i+3])
+	question := query[12 : i+4]
+
+	// The header keeps the ID, the opcode and the recursion desired flag, and is authoritative.
+	res := []byte{query[0], query[1], 0x84 | query[2]&0x79, 0, 0, 1, 0, 0, 0, 0, 0, 0}
+	res = append(res, question...)
+	var records []string
+	if qclass != dnsClassIN || qtype != dnsTypeTXT {
+		res[3] = dnsRcodeNI
+		return res
+	}
+	if records = p.TXT(normalizeHost(strings.Join(labels, "."))); len(records) == 0 {
+		res[3] = dnsRcodeNXD
+		return res
+	}
+	for _, record := range records {
+		var rdata []byte
+		for rest := record; len(rdata) == 0 || rest != ""; {
+			chunk := rest
+			if len(chunk) > 255 {
+				chunk = chunk[:255]
+			}
+			rdata = append(append(rdata, byte(len(chunk))), chunk...)
+			rest = rest[len(chunk):]
+		}
+		// The name points back to the question, the TTL is zero so a changed record is seen at once.
+		res = append(res, 0xc0, 12, 0, dnsTypeTXT, 0, dnsClassIN, 0, 0, 0, 0, byte(len(rdata)>>8), byte(len(rdata)))
+		res = append(res, rdata...)
+		res[7]++
+	}
+	return res
+}
@@ -0,0 +1,247 @@
+package digicert
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serveTestDNS serves provider on a local port for the duration of the test.
+func serveTestDNS(t *testing.T, provider *MemoryDNSProvider) string {
+	conn, err := ServeMemoryDNS("127.0.0.1:0", provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn.LocalAddr().String()
+}
+
+func TestServeMemoryDNS(t *testing.T) {
+	provider := new(MemoryDNSProvider)
+	server := serveTestDNS(t, provider)
+	provider.Present("_dnsauth.example.com", "abc")
+	provider.Present("_dnsauth.example.com", strings.Repeat("x", 300))
+
+	ctx := context.Background()
+	if !servesTXT(ctx, []string{server}, "_dnsauth.example.com", "abc") {
+		t.Fatal("The TXT record is not served")
+	}
+	if !servesTXT(ctx, []string{server}, "_DNSAUTH.Example.com.", strings.Repeat("x", 300)) {
+		t.Fatal("A TXT record over 255 bytes is not served in one piece")
+	}
+	if servesTXT(ctx, []string{server}, "_dnsauth.other.com", "abc") {
+		t.Fatal("A record of another name is served")
+	}
+	provider.CleanUp("_dnsauth.example.com", "abc")
+	if servesTXT(ctx, []string{server}, "_dnsauth.example.com", "abc") {
+		t.Fatal("A removed record is still served")
+	}
+}
+
+// newTestDNSSolver returns a solver publishing to a local DNS server, polling every few milliseconds.
+func newTestDNSSolver(t *testing.T, c *Client) (*DNSSolver, *MemoryDNSProvider) {
+	provider := new(MemoryDNSProvider)
+	return &DNSSolver{
+		Client:             c,
+		Provider:           provider,
+		RecordPrefix:       "_dnsauth",
+		Nameservers:        []string{serveTestDNS(t, provider)},
+		PropagationTimeout: time.Second,
+		PollInterval:       5 * time.Millisecond,
+		CheckTimeout:       time.Second,
+	}, provider
+}
+
+func TestDNSSolverSolveOrder(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s, provider := newTestDNSSolver(t, c)
+	api.reply("GET /order/certificate/1", 200, map[string]interface{}{
+		"id":          1,
+		"certificate": map[string]interface{}{"common_name": "example.com", "dns_names": []string{"example.com", "www.example.com"}},
+	})
+	api.reply("PUT /order/certificate/1/dcv-method", 200, map[string]string{"dcv_random_value": "random"})
+	var checks int32
+	api.handle("PUT /order/certificate/1/check-dcv", func(w http.ResponseWriter, r *http.Request) {
+		// DigiCert must still see the records while it reports the DCV pending.
+		for _, fqdn := range []string{"_dnsauth.example.com", "_dnsauth.www.example.com"} {
+			if !contains(provider.TXT(fqdn), "random") {
+				t.Errorf("%s was removed before the DCV completed", fqdn)
+			}
+		}
+		status := "pending"
+		if atomic.AddInt32(&checks, 1) == 3 {
+			status = "complete"
+		}
+		writeJSON(w, 200, map[string]interface{}{"order_status": "pending", "dcv_status": status})
+	})
+
+	res, err := s.SolveOrder(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.DcvStatus != "complete" || checks != 3 {
+		t.Fatalf("The DCV is %q after %d checks", res.DcvStatus, checks)
+	}
+	var method DVChangeDCVMethodRequest
+	api.body("PUT /order/certificate/1/dcv-method", &method)
+	if method.DcvMethod != DCVDNSTXTToken {
+		t.Errorf("The DCV method is %q", method.DcvMethod)
+	}
+	if records := provider.TXT("_dnsauth.example.com"); len(records) != 0 {
+		t.Errorf("The records are left after the DCV completed: %v", records)
+	}
+}
+
+func TestDNSSolverSolveDomain(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s, provider := newTestDNSSolver(t, c)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.com"})
+	api.reply("PUT /domain/5/dcv/method", 200, map[string]interface{}{"dcv_token": map[string]string{"token": "token", "status": "pending"}})
+	api.handle("PUT /domain/5/dcv/validate-token", func(w http.ResponseWriter, r *http.Request) {
+		if !contains(provider.TXT("_dnsauth.example.com"), "token") {
+			t.Error("The record was removed before the DCV completed")
+		}
+		writeJSON(w, 200, map[string]string{"status": "validated", "dcv_status": "complete"})
+	})
+
+	if _, err := s.SolveDomain(context.Background(), "5"); err != nil {
+		t.Fatal(err)
+	}
+	var check DomainDCVTokenRequest
+	api.body("PUT /domain/5/dcv/validate-token", &check)
+	if check.DcvMethod != DCVDNSTXTToken {
+		t.Errorf("The DCV is checked with %q", check.DcvMethod)
+	}
+	if api.count("POST /domain/5/dcv/cname") != 0 {
+		t.Error("The DCV was confirmed through the cname endpoint")
+	}
+	if records := provider.TXT("_dnsauth.example.com"); len(records) != 0 {
+		t.Errorf("The record is left after the DCV completed: %v", records)
+	}
+}
+
+func TestDNSSolverPendingKeepsRecords(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s, provider := newTestDNSSolver(t, c)
+	s.CheckTimeout = 20 * time.Millisecond
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.com"})
+	api.reply("PUT /domain/5/dcv/method", 200, map[string]interface{}{"dcv_token": map[string]string{"token": "token"}})
+	api.reply("PUT /domain/5/dcv/validate-token", 200, map[string]string{"status": "pending", "dcv_status": "pending"})
+
+	res, err := s.SolveDomain(context.Background(), "5")
+	if err == nil || !strings.Contains(err.Error(), "left in place") {
+		t.Fatalf("SolveDomain error = %v, want a pending DCV", err)
+	}
+	if res == nil || res.DcvStatus != "pending" {
+		t.Errorf("The last check is not returned: %+v", res)
+	}
+	if !contains(provider.TXT("_dnsauth.example.com"), "token") {
+		t.Error("The record was removed while the DCV is pending")
+	}
+}
+
+func TestDNSSolverCanceled(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s, provider := newTestDNSSolver(t, c)
+	// The nameserver never serves the record, so only the context ends the wait.
+	s.Nameservers = []string{serveTestDNS(t, new(MemoryDNSProvider))}
+	s.PropagationTimeout = time.Hour
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.com"})
+	api.reply("PUT /domain/5/dcv/method", 200, map[string]interface{}{"dcv_token": map[string]string{"token": "token"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.SolveDomain(ctx, "5"); err != context.DeadlineExceeded {
+		t.Fatalf("SolveDomain error = %v, want the context error", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("The propagation wait ignored the context")
+	}
+	// DigiCert was never asked to check, the record is removed.
+	if records := provider.TXT("_dnsauth.example.com"); len(records) != 0 {
+		t.Errorf("The record is left after a failed propagation: %v", records)
+	}
+	if api.count("PUT /domain/5/dcv/validate-token") != 0 {
+		t.Error("The DCV was checked before the record propagated")
+	}
+}
+
+func TestMemoryDNSAnswerMalformed(t *testing.T) {
+	p := new(MemoryDNSProvider)
+	for _, query := range [][]byte{nil, make([]byte, 11), {0, 1, 0x80, 0, 0, 1, 0, 0, 0, 0, 0, 0}, {0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 5, 'a'}} {
+		if res := p.answer(query); res != nil {
+			t.Errorf("answer(%x) = %x, want nil", query, res)
+		}
+	}
+	// An A query is not implemented.
+	query := []byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 1, 'a', 0, 0, 1, 0, 1}
+	if res := p.answer(query); len(res) < 12 || res[0] != 0 || res[1] != 1 || res[3] != dnsRcodeNI {
+		t.Errorf("answer(A) = %x, want not implemented", res)
+	}
+}
+
+func TestDNSSolverSolveOrderAPIErrors(t *testing.T) {
+	apiError := map[string]interface{}{"errors": []map[string]string{{"code": "not_found|order", "message": "Order not found."}}}
+	for _, test := range []struct {
+		name  string
+		setup func(api *fakeAPI)
+	}{
+		{"order", func(api *fakeAPI) {
+			api.reply("GET /order/certificate/1", 200, apiError)
+		}},
+		{"method change", func(api *fakeAPI) {
+			api.reply("PUT /order/certificate/1/dcv-method", 200, apiError)
+		}},
+		{"random value", func(api *fakeAPI) {
+			api.reply("PUT /order/certificate/1/dcv-method", 200, map[string]string{})
+			api.reply("PUT /order/certificate/1/dcv-random-value", 200, apiError)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			api, c := newFakeAPI(t)
+			s, provider := newTestDNSSolver(t, c)
+			api.reply("GET /order/certificate/1", 200, map[string]interface{}{"id": 1, "certificate": map[string]interface{}{"common_name": "example.com"}})
+			test.setup(api)
+			_, err := s.SolveOrder(context.Background(), "1")
+			if err == nil || !strings.Contains(err.Error(), "not found") {
+				t.Fatalf("SolveOrder error = %v, want the API error", err)
+			}
+			if records := provider.TXT("_dnsauth.example.com"); len(records) != 0 {
+				t.Errorf("Records were published after an API error: %v", records)
+			}
+		})
+	}
+}
+
+func TestDNSSolverSolveOrderCancelsCalls(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s, _ := newTestDNSSolver(t, c)
+	api.handle("GET /order/certificate/1", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Error("The order request was not canceled with the context")
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.SolveOrder(ctx, "1"); err == nil {
+		t.Fatal("SolveOrder succeeded with a canceled context")
+	}
+}
+
+func TestDNSSolverSolveDomainAPIError(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s, _ := newTestDNSSolver(t, c)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"errors": []map[string]string{{"code": "not_found", "message": "Domain not found."}}})
+	if _, err := s.SolveDomain(context.Background(), "5"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("SolveDomain error = %v, want the API error", err)
+	}
+	if api.count("PUT /domain/5/dcv/method") != 0 {
+		t.Error("The DCV went on after an API error")
+	}
+}
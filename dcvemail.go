@@ -0,0 +1,157 @@
+package digicert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EmailDCV exports drives the email Domain Control Validation (DCV) of a domain: pick the recipients scope, send or resend the invitations, and poll the domain until its DCV is complete.
+// It is safe for concurrent use.
+type EmailDCV struct {
+	client   *Client
+	domainID string
+
+	mu          sync.Mutex
+	nameScope   string
+	invitations []DCVInvitation
+}
+
+// NewEmailDCV exports starts an email DCV workflow for domainID. The calls of the workflow are canceled with the context they are given.
+func (c *Client) NewEmailDCV(domainID string) *EmailDCV {
+	return &EmailDCV{
+		client:   c.clone(),
+		domainID: domainID,
+	}
+}
+
+// Candidates returns the WHOIS and constructed base addresses DigiCert can send the DCV email to, with the current name scope.
+func (w *EmailDCV) Candidates(ctx context.Context) (*DomainControlEmailsResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	res, err := w.client.withContext(ctx).GetDomainControlEmails(w.domainID)
+	if err != nil {
+		return nil, err
+	}
+	return res, res.err()
+}
+
+// Send switches the domain to the email method and sends the invitations for nameScope, the domain name itself or one of its parents. An empty nameScope keeps the domain name.
+func (w *EmailDCV) Send(ctx context.Context, nameScope string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	client := w.client.withContext(ctx)
+	domain, err := client.ViewADomain(w.domainID)
+	if err != nil {
+		return err
+	}
+	if err := domain.err(); err != nil {
+		return err
+	}
+	name := strings.ToLower(domain.Name)
+	scope := strings.ToLower(strings.TrimSuffix(nameScope, "."))
+	if scope == "" {
+		scope = name
+	}
+	if scope != name && !strings.HasSuffix(name, "."+scope) {
+		return errors.New("The name scope must be the domain or one of its parents")
+	}
+	method, err := client.SetDomainDCVMethod(w.domainID, DCVEmail)
+	if err != nil {
+		return err
+	}
+	if err := method.err(); err != nil {
+		return err
+	}
+	w.nameScope = scope
+	return w.send(client)
+}
+
+// Resend sends the invitations again for the name scope of the last Send.
+func (w *EmailDCV) Resend(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.nameScope == "" {
+		return errors.New("The DCV emails must be sent before they are resent")
+	}
+	return w.send(w.client.withContext(ctx))
+}
+
+func (w *EmailDCV) send(client *Client) error {
+	res, err := client.SendDCVEmails(w.domainID, &ResendDCVEmailReqeust{NameScope: w.nameScope})
+	if err != nil {
+		return err
+	}
+	if err := res.err(); err != nil {
+		return err
+	}
+	w.invitations = append(w.invitations, res.DcvInvitations...)
+	return nil
+}
+
+// Invitations returns the invitations sent so far.
+func (w *EmailDCV) Invitations() []DCVInvitation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]DCVInvitation(nil), w.invitations...)
+}
+
+// Approve submits the DCV approval for the tracked invitations.
+func (w *EmailDCV) Approve(ctx context.Context) (*ApproveStatuesResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	res, err := w.client.withContext(ctx).ApproveEmail(w.domainID, &EmailApprove{
+		Method:         DCVEmail,
+		NameScope:      w.nameScope,
+		DcvInvitations: w.invitations,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, res.err()
+}
+
+// Complete reports whether every validation of the domain has completed its DCV.
+func (w *EmailDCV) Complete(ctx context.Context) (bool, *ViewADomainResponse, error) {
+	return w.complete(w.client.withContext(ctx))
+}
+
+func (w *EmailDCV) complete(client *Client) (bool, *ViewADomainResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	domain, err := client.ViewADomain(w.domainID)
+	if err != nil {
+		return false, nil, err
+	}
+	if err := domain.err(); err != nil {
+		return false, nil, err
+	}
+	if len(domain.Validations) == 0 {
+		return false, domain, nil
+	}
+	for _, v := range domain.Validations {
+		if v.DcvStatus != DCVStatusComplete {
+			return false, domain, nil
+		}
+	}
+	return true, domain, nil
+}
+
+// Wait polls Complete every interval (10 seconds when not positive) until the DCV is complete, timeout (5 minutes when not positive) elapses or ctx is done.
+func (w *EmailDCV) Wait(ctx context.Context, interval, timeout time.Duration) (*ViewADomainResponse, error) {
+	client := w.client.withContext(ctx)
+	var domain *ViewADomainResponse
+	err := pollDCV(ctx, interval, timeout, func() (bool, error) {
+		done, d, err := w.complete(client)
+		if d != nil {
+			domain = d
+		}
+		return done, err
+	})
+	if err != nil {
+		return domain, errors.New("The email DCV of domain " + w.domainID + " is not complete: " + err.Error())
+	}
+	return domain, nil
+}
@@ -0,0 +1,203 @@
+package digicert
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailDCVCandidates(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain/5/dcv/emails", 200, map[string]interface{}{
+		"name_scope":   "example.com",
+		"base_emails":  []string{"admin@example.com", "webmaster@example.com"},
+		"whois_emails": []string{"owner@example.com"},
+	})
+
+	res, err := c.NewEmailDCV("5").Candidates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NameScope != "example.com" || len(res.BaseEmails) != 2 || !reflect.DeepEqual(res.WhoisEmails, []string{"owner@example.com"}) {
+		t.Errorf("Candidates = %+v", res)
+	}
+
+	api.reply("GET /domain/5/dcv/emails", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_domain", "message": "Invalid domain."}}})
+	if _, err := c.NewEmailDCV("5").Candidates(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid_domain") {
+		t.Errorf("Candidates error = %v, want the API error", err)
+	}
+}
+
+func TestEmailDCVNameScope(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "shop.example.com"})
+	w := c.NewEmailDCV("5")
+
+	for _, scope := range []string{"other.com", "op.example.com", "www.shop.example.com"} {
+		if err := w.Send(context.Background(), scope); err == nil {
+			t.Errorf("Send(%q) succeeded outside the domain", scope)
+		}
+	}
+	if n := api.count("PUT /domain/5/dcv/method"); n != 0 {
+		t.Errorf("The DCV method was changed %d times for a rejected name scope", n)
+	}
+	if err := w.Resend(context.Background()); err == nil {
+		t.Error("Resend succeeded before Send")
+	}
+}
+
+func TestEmailDCVSendAndResend(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "shop.example.com"})
+	api.reply("PUT /domain/5/dcv/method", 200, map[string]interface{}{})
+	sent := 0
+	api.handle("POST /domain/5/dcv/emails", func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		writeJSON(w, 200, map[string]interface{}{"dcv_invitations": []map[string]interface{}{{"invitation_id": sent, "email": "admin@example.com"}}})
+	})
+	w := c.NewEmailDCV("5")
+
+	if err := w.Send(context.Background(), "Example.com."); err != nil {
+		t.Fatal(err)
+	}
+	var method DomainDCVTokenRequest
+	api.body("PUT /domain/5/dcv/method", &method)
+	if method.DcvMethod != DCVEmail {
+		t.Errorf("The DCV method was changed to %q", method.DcvMethod)
+	}
+	var scope ResendDCVEmailReqeust
+	api.body("POST /domain/5/dcv/emails", &scope)
+	if scope.NameScope != "example.com" {
+		t.Errorf("The emails were sent for the name scope %q", scope.NameScope)
+	}
+	if err := w.Resend(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Invitations(); len(got) != 2 || got[0].InvitationID != 1 || got[1].InvitationID != 2 {
+		t.Errorf("Invitations = %+v", got)
+	}
+
+	api.reply("POST /domain/5/dcv", 200, map[string]interface{}{"dcv_status": "complete"})
+	if _, err := w.Approve(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var approve EmailApprove
+	api.body("POST /domain/5/dcv", &approve)
+	if approve.NameScope != "example.com" || len(approve.DcvInvitations) != 2 {
+		t.Errorf("The approval sent %+v", approve)
+	}
+}
+
+func TestEmailDCVSendMethodRejected(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.com"})
+	api.reply("PUT /domain/5/dcv/method", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_dcv_method", "message": "Invalid DCV method."}}})
+
+	if err := c.NewEmailDCV("5").Send(context.Background(), ""); err == nil || !strings.Contains(err.Error(), "invalid_dcv_method") {
+		t.Errorf("Send error = %v, want the API error", err)
+	}
+	if n := api.count("POST /domain/5/dcv/emails"); n != 0 {
+		t.Errorf("The emails were sent %d times after the method change failed", n)
+	}
+
+	// A domain the API reports an error for is not sent to.
+	api.reply("GET /domain/6", 200, map[string]interface{}{"errors": []map[string]string{{"code": "not_found", "message": "Item not found."}}})
+	if err := c.NewEmailDCV("6").Send(context.Background(), ""); err == nil || !strings.Contains(err.Error(), "not_found") {
+		t.Errorf("Send error = %v, want the API error", err)
+	}
+}
+
+func TestEmailDCVWait(t *testing.T) {
+	api, c := newFakeAPI(t)
+	checks := 0
+	api.handle("GET /domain/5", func(w http.ResponseWriter, r *http.Request) {
+		status := "pending"
+		if checks++; checks == 3 {
+			status = "complete"
+		}
+		writeJSON(w, 200, map[string]interface{}{"id": 5, "name": "example.com", "validations": []map[string]string{
+			{"type": "ov", "dcv_status": "complete"},
+			{"type": "ev", "dcv_status": status},
+		}})
+	})
+
+	domain, err := c.NewEmailDCV("5").Wait(context.Background(), time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks != 3 || domain == nil || domain.ID != 5 {
+		t.Errorf("Wait = %+v after %d checks", domain, checks)
+	}
+}
+
+func TestEmailDCVWaitCanceled(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.com", "validations": []map[string]string{{"type": "ov", "dcv_status": "pending"}}})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	// A non-positive interval does not poll in a busy loop and the context ends the wait.
+	if _, err := c.NewEmailDCV("5").Wait(ctx, 0, time.Hour); err == nil {
+		t.Fatal("Wait succeeded with a pending DCV")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Wait did not return with the context")
+	}
+	if n := api.count("GET /domain/5"); n != 1 {
+		t.Errorf("The domain was polled %d times", n)
+	}
+}
+
+func TestEmailDCVCanceled(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("GET /domain/5", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Error("The domain request was not canceled with the context")
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.NewEmailDCV("5").Send(ctx, ""); err == nil {
+		t.Fatal("Send succeeded with a canceled context")
+	}
+	if n := api.count("PUT /domain/5/dcv/method"); n != 0 {
+		t.Errorf("The DCV method was changed %d times", n)
+	}
+}
+
+func TestSetDomainDCVMethod(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /domain/5/dcv/method", 204, nil)
+	api.reply("PUT /domain/6/dcv/method", 200, map[string]interface{}{"dcv_token": map[string]string{"token": "random", "status": "pending"}})
+	api.reply("PUT /domain/7/dcv/method", 409, map[string]interface{}{})
+
+	if ok, err := c.ChangeDomainControlMethod("5", &DomainControlMethodRequest{Method: DCVEmail}); !ok || err != nil {
+		t.Errorf("ChangeDomainControlMethod = %v, %v", ok, err)
+	}
+	var method DomainDCVTokenRequest
+	api.body("PUT /domain/5/dcv/method", &method)
+	if method.DcvMethod != DCVEmail {
+		t.Errorf("The DCV method was changed to %q", method.DcvMethod)
+	}
+	if res, err := c.DomainDCVToken("6", DCVDNSTXTToken); err != nil || res.DcvToken.Token != "random" {
+		t.Errorf("DomainDCVToken = %+v, %v", res, err)
+	}
+	if _, err := c.DomainDCVToken("6", DCVEmail); err == nil {
+		t.Error("DomainDCVToken accepted the email method")
+	}
+	if _, err := c.SetDomainDCVMethod("6", DCVMethod("fax")); err == nil {
+		t.Error("SetDomainDCVMethod accepted an unknown method")
+	}
+	if ok, err := c.ChangeDomainControlMethod("7", &DomainControlMethodRequest{Method: DCVEmail}); ok || err == nil || !strings.Contains(err.Error(), "status 409") {
+		t.Errorf("ChangeDomainControlMethod = %v, %v, want the status error", ok, err)
+	}
+	if n := api.count("POST /domain/5/dcv/method"); n != 0 {
+		t.Errorf("The DCV method was posted %d times", n)
+	}
+}
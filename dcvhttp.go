@@ -0,0 +1,222 @@
+package digicert
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPTokenPath is the location DigiCert fetches the random value from with the http-token DCV method.
+const HTTPTokenPath = "/.well-known/pki-validation/fileauth.txt"
+
+// HTTPTokenHandler exports an http.Handler serving the random values of the http-token DCV method, each value is only served for its host.
+// The zero value serves no token.
+type HTTPTokenHandler struct {
+	mu     sync.RWMutex
+	tokens map[string]httpToken
+}
+
+type httpToken struct {
+	value   string
+	expires time.Time
+}
+
+// NewHTTPTokenHandler exports creates an empty token handler.
+func NewHTTPTokenHandler() *HTTPTokenHandler {
+	return new(HTTPTokenHandler)
+}
+
+// Register serves value for host until ttl elapses, a zero ttl serves it until Remove.
+func (h *HTTPTokenHandler) Register(host, value string, ttl time.Duration) {
+	t := httpToken{value: value}
+	if ttl > 0 {
+		t.expires = time.Now().Add(ttl)
+	}
+	h.mu.Lock()
+	if h.tokens == nil {
+		h.tokens = make(map[string]httpToken)
+	}
+	h.tokens[normalizeHost(host)] = t
+	h.mu.Unlock()
+}
+
+// Remove stops serving the value of host.
+func (h *HTTPTokenHandler) Remove(host string) {
+	h.mu.Lock()
+	delete(h.tokens, normalizeHost(host))
+	h.mu.Unlock()
+}
+
+// ServeHTTP answers the token of the request host on HTTPTokenPath and 404 otherwise.
+func (h *HTTPTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != HTTPTokenPath || (r.Method != "GET" && r.Method != "HEAD") {
+		http.NotFound(w, r)
+		return
+	}
+	host := normalizeHost(r.Host)
+	h.mu.RLock()
+	t, ok := h.tokens[host]
+	h.mu.RUnlock()
+	if ok && !t.expires.IsZero() && time.Now().After(t.expires) {
+		h.Remove(host)
+		ok = false
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(t.value))
+}
+
+// normalizeHost lowercases host and strips the port and a wildcard label.
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(host), "*."), ".")
+}
+
+// ServeHTTPToken exports starts a server on addr (usually ":80") answering with handler, close the returned server once validation is done.
+// The Addr of the returned server is the address listened on, e.g. with the port picked for ":0".
+func ServeHTTPToken(addr string, handler *HTTPTokenHandler) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{
+		Addr:              ln.Addr().String(),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go srv.Serve(ln)
+	return srv, nil
+}
+
+// HTTPSolver exports completes the http-token Domain Control Validation (DCV) of an order or a domain.
+// It registers the random value on Handler, waits until it is reachable over HTTP on every name, asks DigiCert to check and removes the tokens once DigiCert reports the DCV complete.
+type HTTPSolver struct {
+	Client  *Client
+	Handler *HTTPTokenHandler
+	// HTTPClient probes the token URLs, defaults to a client with a 10 seconds timeout.
+	HTTPClient *http.Client
+	// TTL bounds how long a token is served when the solver does not get to remove it, defaults to 1 hour.
+	TTL time.Duration
+	// ReachableTimeout defaults to 2 minutes and PollInterval to 5 seconds.
+	ReachableTimeout time.Duration
+	PollInterval     time.Duration
+	// CheckTimeout bounds how long DigiCert is asked again while the DCV is pending, defaults to 5 minutes.
+	CheckTimeout time.Duration
+}
+
+// SolveOrder exports validates all names of a pending DV order with the http-token method.
+// DigiCert does not validate wildcard names over HTTP, an order with one is rejected before its DCV method is changed.
+// When the DCV is still pending after CheckTimeout, or the check fails, the tokens are served until TTL for DigiCert to finish and an error is returned along with the last check.
+func (s *HTTPSolver) SolveOrder(ctx context.Context, orderID string) (*DVCheckDCVResponse, error) {
+	return s.challenge().order(ctx, s.Client, orderID)
+}
+
+// SolveDomain exports validates a domain with the http-token method.
+// When the DCV is still pending after CheckTimeout, or the check fails, the token is served until TTL for DigiCert to finish and an error is returned along with the last check.
+func (s *HTTPSolver) SolveDomain(ctx context.Context, domainID string) (*ApproveStatuesResponse, error) {
+	return s.challenge().domain(ctx, s.Client, domainID)
+}
+
+func (s *HTTPSolver) challenge() *dcvChallenge {
+	return &dcvChallenge{
+		method:   DCVHTTPToken,
+		accept:   acceptHTTPTokenName,
+		publish:  s.serve,
+		interval: s.pollInterval(),
+		timeout:  s.CheckTimeout,
+		kept:     "its tokens are still served",
+	}
+}
+
+// acceptHTTPTokenName rejects wildcard names, DigiCert only validates them with the email and DNS methods.
+func acceptHTTPTokenName(name string) error {
+	if strings.HasPrefix(strings.TrimSpace(name), "*.") {
+		return errors.New("The http-token DCV method cannot validate the wildcard name " + name + ", use dns-txt-token instead")
+	}
+	return nil
+}
+
+func (s *HTTPSolver) pollInterval() time.Duration {
+	if s.PollInterval == 0 {
+		return 5 * time.Second
+	}
+	return s.PollInterval
+}
+
+// serve registers value for every distinct name and waits until all of them are reachable, the returned cleanup removes the tokens.
+func (s *HTTPSolver) serve(ctx context.Context, names []string, value string) (func(), error) {
+	if s.Handler == nil {
+		return func() {}, errors.New("The HTTPSolver has no Handler to serve the http-token")
+	}
+	if value == "" {
+		return func() {}, errors.New("DigiCert returned no random value")
+	}
+	ttl := s.TTL
+	if ttl == 0 {
+		ttl = time.Hour
+	}
+	var hosts []string
+	cleanup := func() {
+		for _, host := range hosts {
+			s.Handler.Remove(host)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		host := normalizeHost(name)
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		s.Handler.Register(host, value, ttl)
+		hosts = append(hosts, host)
+	}
+	for _, host := range hosts {
+		if err := s.waitReachable(ctx, host, value); err != nil {
+			return cleanup, err
+		}
+	}
+	return cleanup, nil
+}
+
+// waitReachable polls http://host/.well-known/pki-validation/fileauth.txt until it answers value.
+func (s *HTTPSolver) waitReachable(ctx context.Context, host, value string) error {
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	timeout := s.ReachableTimeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		req, err := http.NewRequest("GET", "http://"+host+HTTPTokenPath, nil)
+		if err != nil {
+			return err
+		}
+		if res, err := client.Do(req.WithContext(ctx)); err == nil {
+			body, err := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if err == nil && res.StatusCode == http.StatusOK && strings.TrimSpace(string(body)) == value {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return errors.New("The http-token of " + host + " is not reachable")
+		}
+		if err := sleep(ctx, s.pollInterval()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package digicert
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestHTTPSolver returns a solver whose handler is served locally, every host resolves to it.
+func newTestHTTPSolver(t *testing.T, c *Client) *HTTPSolver {
+	handler := NewHTTPTokenHandler()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	addr := srv.Listener.Addr().String()
+	return &HTTPSolver{
+		Client:  c,
+		Handler: handler,
+		HTTPClient: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		}},
+		ReachableTimeout: time.Second,
+		PollInterval:     5 * time.Millisecond,
+		CheckTimeout:     time.Second,
+	}
+}
+
+// served reports whether the handler answers value for host.
+func served(h *HTTPTokenHandler, host, value string) bool {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://"+host+HTTPTokenPath, nil))
+	return w.Code == http.StatusOK && w.Body.String() == value
+}
+
+func TestHTTPSolverSolveDomain(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s := newTestHTTPSolver(t, c)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.com"})
+	api.reply("PUT /domain/5/dcv/method", 200, map[string]interface{}{"dcv_token": map[string]string{"token": "token"}})
+	checks := 0
+	api.handle("PUT /domain/5/dcv/validate-token", func(w http.ResponseWriter, r *http.Request) {
+		if !served(s.Handler, "example.com", "token") {
+			t.Error("The token was removed before the DCV completed")
+		}
+		status := "pending"
+		if checks++; checks == 2 {
+			status = "complete"
+		}
+		writeJSON(w, 200, map[string]string{"dcv_status": status})
+	})
+
+	if _, err := s.SolveDomain(context.Background(), "5"); err != nil {
+		t.Fatal(err)
+	}
+	if checks != 2 {
+		t.Errorf("The DCV was checked %d times", checks)
+	}
+	if served(s.Handler, "example.com", "token") {
+		t.Error("The token is still served after the DCV completed")
+	}
+}
+
+func TestHTTPSolverPendingKeepsToken(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s := newTestHTTPSolver(t, c)
+	s.CheckTimeout = 20 * time.Millisecond
+	api.reply("GET /order/certificate/1", 200, map[string]interface{}{
+		"id":          1,
+		"certificate": map[string]interface{}{"common_name": "example.com", "dns_names": []string{"www.example.com"}},
+	})
+	api.reply("PUT /order/certificate/1/dcv-method", 200, map[string]string{"dcv_random_value": "random"})
+	api.reply("PUT /order/certificate/1/check-dcv", 200, map[string]string{"dcv_status": "pending"})
+
+	if _, err := s.SolveOrder(context.Background(), "1"); err == nil || !strings.Contains(err.Error(), "still served") {
+		t.Fatalf("SolveOrder error = %v, want a pending DCV", err)
+	}
+	for _, host := range []string{"example.com", "www.example.com"} {
+		if !served(s.Handler, host, "random") {
+			t.Errorf("The token of %s was removed while the DCV is pending", host)
+		}
+	}
+}
+
+func TestHTTPSolverAPIErrors(t *testing.T) {
+	apiError := map[string]interface{}{"errors": []map[string]string{{"code": "not_found", "message": "Item not found."}}}
+	api, c := newFakeAPI(t)
+	s := newTestHTTPSolver(t, c)
+	api.reply("GET /order/certificate/1", 200, apiError)
+	api.reply("GET /domain/5", 200, apiError)
+
+	if _, err := s.SolveOrder(context.Background(), "1"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("SolveOrder error = %v, want the API error", err)
+	}
+	if _, err := s.SolveDomain(context.Background(), "5"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("SolveDomain error = %v, want the API error", err)
+	}
+	if api.count("PUT /order/certificate/1/dcv-method") != 0 || api.count("PUT /domain/5/dcv/method") != 0 {
+		t.Error("The DCV method was changed after an API error")
+	}
+}
+
+func TestHTTPSolverCancelsCalls(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s := newTestHTTPSolver(t, c)
+	api.handle("GET /domain/5", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Error("The domain request was not canceled with the context")
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.SolveDomain(ctx, "5"); err == nil {
+		t.Fatal("SolveDomain succeeded with a canceled context")
+	}
+}
+
+func TestHTTPSolverRejectsWildcards(t *testing.T) {
+	api, c := newFakeAPI(t)
+	s := newTestHTTPSolver(t, c)
+	api.reply("GET /order/certificate/1", 200, map[string]interface{}{
+		"id":          1,
+		"certificate": map[string]interface{}{"common_name": "example.com", "dns_names": []string{"*.example.com"}},
+	})
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "*.example.com"})
+
+	if _, err := s.SolveOrder(context.Background(), "1"); err == nil || !strings.Contains(err.Error(), "wildcard") {
+		t.Errorf("SolveOrder error = %v, want a wildcard error", err)
+	}
+	if _, err := s.SolveDomain(context.Background(), "5"); err == nil || !strings.Contains(err.Error(), "wildcard") {
+		t.Errorf("SolveDomain error = %v, want a wildcard error", err)
+	}
+	if api.count("PUT /order/certificate/1/dcv-method") != 0 || api.count("PUT /domain/5/dcv/method") != 0 {
+		t.Error("The DCV method was changed for a wildcard name")
+	}
+	if len(s.Handler.tokens) != 0 {
+		t.Errorf("Tokens were registered for a wildcard name: %v", s.Handler.tokens)
+	}
+}
+
+func TestHTTPTokenHandlerZeroValue(t *testing.T) {
+	var h HTTPTokenHandler
+	if served(&h, "example.com", "") {
+		t.Error("An empty handler serves a token")
+	}
+	h.Remove("example.com")
+	h.Register("Example.com:80", "token", 0)
+	if !served(&h, "example.com", "token") || served(&h, "www.example.com", "token") {
+		t.Error("The token is not served for its host only")
+	}
+	h.Register("www.example.com", "expired", -time.Second)
+	h.Register("shop.example.com", "short", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if !served(&h, "www.example.com", "expired") || served(&h, "shop.example.com", "short") {
+		t.Error("The token ttl is not honoured")
+	}
+	h.Remove("EXAMPLE.COM")
+	if served(&h, "example.com", "token") {
+		t.Error("The token is served after Remove")
+	}
+}
+
+func TestHTTPSolverWithoutHandler(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain/5", 200, map[string]interface{}{"id": 5, "name": "example.com"})
+	api.reply("PUT /domain/5/dcv/method", 200, map[string]interface{}{"dcv_token": map[string]string{"token": "token"}})
+	s := &HTTPSolver{Client: c}
+	if _, err := s.SolveDomain(context.Background(), "5"); err == nil || !strings.Contains(err.Error(), "no Handler") {
+		t.Errorf("SolveDomain error = %v, want a missing handler", err)
+	}
+}
+
+func TestServeHTTPToken(t *testing.T) {
+	handler := NewHTTPTokenHandler()
+	handler.Register("example.com", "token", time.Minute)
+	srv, err := ServeHTTPToken("127.0.0.1:0", handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	get := func(host, path string) (int, string) {
+		req, err := http.NewRequest("GET", "http://"+srv.Addr+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = host
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return res.StatusCode, string(body)
+	}
+	if status, body := get("example.com", HTTPTokenPath); status != http.StatusOK || body != "token" {
+		t.Errorf("The token URL answered %d %q", status, body)
+	}
+	if status, _ := get("example.com", "/"); status != http.StatusNotFound {
+		t.Errorf("Another path answered %d", status)
+	}
+	if status, _ := get("www.example.com", HTTPTokenPath); status != http.StatusNotFound {
+		t.Errorf("Another host answered %d", status)
+	}
+}
@@ -0,0 +1,144 @@
+package digicert
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// dcvChallenge presents a token DCV method solved by publishing the random value on every name of an order or a domain.
+// The solvers only differ by how the value is published and withdrawn, the calls to DigiCert are shared.
+type dcvChallenge struct {
+	method DCVMethod
+	// accept rejects a name the method cannot validate, before the DCV method of the order or domain is changed. Nil accepts every name.
+	accept func(name string) error
+	// publish makes value available for names, the returned cleanup withdraws it and must be called even when publish fails.
+	publish func(ctx context.Context, names []string, value string) (func(), error)
+	// interval and timeout of the checks, see pollDCV.
+	interval, timeout time.Duration
+	// kept tells what stays published when the DCV does not complete, e.g. "its TXT records are left in place".
+	kept string
+}
+
+// order validates all names of a pending DV order.
+func (d *dcvChallenge) order(ctx context.Context, c *Client, orderID string) (*DVCheckDCVResponse, error) {
+	client := c.withContext(ctx)
+	order, err := client.ViewOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := order.err(); err != nil {
+		return nil, err
+	}
+	names := append([]string{order.Certificate.CommonName}, order.Certificate.DNSNames...)
+	if err := d.acceptAll(names); err != nil {
+		return nil, err
+	}
+	random, err := client.DVChangeDCVMethod(orderID, d.method)
+	if err != nil {
+		return nil, err
+	}
+	if err := random.err(); err != nil {
+		return nil, err
+	}
+	if random.DcvRandomValue == "" {
+		if random, err = client.DVDCVRandomValue(orderID); err != nil {
+			return nil, err
+		}
+		if err := random.err(); err != nil {
+			return nil, err
+		}
+	}
+	cleanup, err := d.publish(ctx, names, random.DcvRandomValue)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	var res *DVCheckDCVResponse
+	err = pollDCV(ctx, d.interval, d.timeout, func() (bool, error) {
+		if res, err = client.DVCheckDCV(orderID); err != nil {
+			return false, err
+		}
+		return res.DcvStatus == DCVStatusComplete, res.err()
+	})
+	if err != nil {
+		return res, errors.New("The DCV of order " + orderID + " is not complete, " + d.kept + ": " + err.Error())
+	}
+	cleanup()
+	return res, nil
+}
+
+// domain validates a domain.
+func (d *dcvChallenge) domain(ctx context.Context, c *Client, domainID string) (*ApproveStatuesResponse, error) {
+	client := c.withContext(ctx)
+	domain, err := client.ViewADomain(domainID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.err(); err != nil {
+		return nil, err
+	}
+	if err := d.acceptAll([]string{domain.Name}); err != nil {
+		return nil, err
+	}
+	token, err := client.DomainDCVToken(domainID, d.method)
+	if err != nil {
+		return nil, err
+	}
+	if err := token.err(); err != nil {
+		return nil, err
+	}
+	cleanup, err := d.publish(ctx, []string{domain.Name}, token.DcvToken.Token)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	var res *ApproveStatuesResponse
+	err = pollDCV(ctx, d.interval, d.timeout, func() (bool, error) {
+		if res, err = client.CheckDomainDCV(domainID, d.method); err != nil {
+			return false, err
+		}
+		return res.complete(), res.err()
+	})
+	if err != nil {
+		return res, errors.New("The DCV of domain " + domainID + " is not complete, " + d.kept + ": " + err.Error())
+	}
+	cleanup()
+	return res, nil
+}
+
+func (d *dcvChallenge) acceptAll(names []string) error {
+	if d.accept == nil {
+		return nil
+	}
+	for _, name := range names {
+		if err := d.accept(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pollDCV calls check every interval (10 seconds by default) until it reports the DCV complete, fails, or timeout (5 minutes by default) elapses.
+// A non-positive interval or timeout takes the default, so the API is never polled in a busy loop.
+func pollDCV(ctx context.Context, interval, timeout time.Duration, check func() (bool, error)) error {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.New("the DCV is still pending")
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
@@ -3,15 +3,19 @@ package digicert
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
-const baseURI = "https://www.digicert.com/services/v2/"
+// baseURI is a variable so tests can point the client at a local stand-in.
+var baseURI = "https://www.digicert.com/services/v2/"
 
 // Client as standard client
 type Client struct {
@@ -20,7 +24,11 @@ type Client struct {
 	request    interface{}
 	result     interface{}
 	AuthKey    string
-	headers    http.Header
+	// Credentials, when set, supplies the API key of every request instead of AuthKey.
+	Credentials CredentialProvider
+	headers     http.Header
+	// ctx, when set, bounds every request of the client.
+	ctx context.Context
 }
 
 // SchemeValidationErrors provides basic fields for scheme validation errors, the general error handling by return http status codes.
@@ -35,6 +43,98 @@ type SchemeValidationErrors struct {
 	}
 }
 
+// err returns the first scheme validation error reported by the API, or nil.
+func (e *SchemeValidationErrors) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return errors.New(e.Errors[0].Code + ": " + e.Errors[0].Message)
+}
+
+// IDReference presents an object referred to by its ID in a request, e.g. a user or a container.
+type IDReference struct {
+	ID int `json:"id"`
+}
+
+// apiTimeLayouts are the date formats found in CertCentral responses.
+var apiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseAPITime parses a CertCentral date, an empty string is the zero time.
+func parseAPITime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	var err error
+	for _, layout := range apiTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// DateTime is a CertCentral timestamp. It decodes every format of apiTimeLayouts and embeds time.Time for arithmetic, e.g. d.Sub(time.Now()).
+type DateTime struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes a timestamp, null and an empty string are the zero time.
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	t, err := unmarshalAPITime(data)
+	d.Time = t
+	return err
+}
+
+// MarshalJSON encodes the time as RFC 3339, the zero time as null.
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Format(time.RFC3339))
+}
+
+// Date is a CertCentral day such as a validity end, sent as 2006-01-02 or as a timestamp. It embeds time.Time for arithmetic.
+type Date struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes a day or a timestamp, null and an empty string are the zero time.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	t, err := unmarshalAPITime(data)
+	d.Time = t
+	return err
+}
+
+// MarshalJSON encodes the day as 2006-01-02, the zero time as null.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Format("2006-01-02"))
+}
+
+// unmarshalAPITime decodes a JSON string with parseAPITime.
+func unmarshalAPITime(data []byte) (time.Time, error) {
+	if string(data) == "null" {
+		return time.Time{}, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return time.Time{}, err
+	}
+	return parseAPITime(strings.TrimSpace(s))
+}
+
 // New exports digicert new api instance.
 func New(key string) (*Client, error) {
 	if key == "" {
@@ -47,10 +147,44 @@ func New(key string) (*Client, error) {
 
 }
 
+// NewWithCredentials exports digicert new api instance getting its API key from provider on every request.
+func NewWithCredentials(provider CredentialProvider) (*Client, error) {
+	if provider == nil {
+		return nil, errors.New("The digicert api credentials must input")
+	}
+	return &Client{Credentials: provider}, nil
+}
+
+// apiKey returns the API key of the next request.
+func (c *Client) apiKey() (string, error) {
+	if c.Credentials == nil {
+		return c.AuthKey, nil
+	}
+	return c.Credentials.APIKey()
+}
+
+// String describes the client without its API key.
+func (c Client) String() string {
+	key := ""
+	if c.AuthKey != "" || c.Credentials != nil {
+		key = redacted
+	}
+	return "digicert.Client{AuthKey: " + key + "}"
+}
+
+// GoString describes the client without its API key.
+func (c Client) GoString() string {
+	return c.String()
+}
+
 // apiconnect exports a http client dial to digicert api endpoints.
 func (c *Client) makeRequest(method, uri string, headers http.Header) ([]byte, error) {
 	var req *http.Request
-	var err error
+	c.statusCode = 0
+	key, err := c.apiKey()
+	if err != nil {
+		return nil, err
+	}
 	fullURI := baseURI + strings.Trim(uri, "/")
 	// log.Println("fullURI - ", fullURI)
 	if method == "GET" || method == "DELETE" {
@@ -76,7 +210,10 @@ func (c *Client) makeRequest(method, uri string, headers http.Header) ([]byte, e
 	if req.Header.Get("Content-Type") == "" {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	req.Header.Set("X-DC-DEVKEY", c.AuthKey)
+	req.Header.Set("X-DC-DEVKEY", key)
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			PreferServerCipherSuites: true,
@@ -87,7 +224,7 @@ func (c *Client) makeRequest(method, uri string, headers http.Header) ([]byte, e
 	c.client = &http.Client{Transport: tr}
 	res, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, redactError(err, key)
 	}
 	defer res.Body.Close()
 	c.statusCode = res.StatusCode
@@ -115,6 +252,10 @@ func (c *Client) makeRequest(method, uri string, headers http.Header) ([]byte, e
 	if err != nil {
 		return nil, err
 	}
+	// The key is removed in case the API echoes it in an error message.
+	if key != "" && bytes.Contains(data, []byte(key)) {
+		data = bytes.Replace(data, []byte(key), []byte(redacted), -1)
+	}
 	return data, err
 }
 
@@ -125,3 +266,86 @@ func copyHeader(target, source http.Header) {
 		target[k] = vs
 	}
 }
+
+// clone returns a client sharing the credentials and headers of c, a Client keeps the state of the last call so every goroutine needs its own.
+func (c *Client) clone() *Client {
+	return &Client{
+		AuthKey:     c.AuthKey,
+		Credentials: c.Credentials,
+		headers:     c.headers,
+	}
+}
+
+// sleep waits for d unless ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RateLimit presents how many API calls a bulk operation makes in parallel and how often.
+type RateLimit struct {
+	// Concurrency is the number of parallel API calls, defaults to 4.
+	Concurrency int
+	// Interval is the minimum time between two API calls, zero defaults to 200ms and a negative interval starts the calls without waiting.
+	Interval time.Duration
+}
+
+// limits returns the limits with their defaults.
+func (l RateLimit) limits() (int, time.Duration) {
+	concurrency, interval := 4, 200*time.Millisecond
+	if l.Concurrency > 0 {
+		concurrency = l.Concurrency
+	}
+	switch {
+	case l.Interval > 0:
+		interval = l.Interval
+	case l.Interval < 0:
+		interval = 0
+	}
+	return concurrency, interval
+}
+
+// withContext returns a clone of c whose requests are canceled with ctx.
+func (c *Client) withContext(ctx context.Context) *Client {
+	cc := c.clone()
+	cc.ctx = ctx
+	return cc
+}
+
+// throttle calls fn for every index in [0, n) from at most workers goroutines, starting no more than one call per interval.
+func throttle(n, workers int, interval time.Duration, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if tick != nil && i > 0 {
+			<-tick
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,229 @@
+package digicert
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAPI stands in for the CertCentral API: it answers the routes registered as "METHOD /path" and records every call.
+type fakeAPI struct {
+	t      *testing.T
+	mu     sync.Mutex
+	routes map[string]http.HandlerFunc
+	calls  []string
+	bodies map[string][]byte
+	// keys are accepted besides test-key, e.g. a key created during the test.
+	keys map[string]bool
+}
+
+// newFakeAPI starts a fake API and returns a client talking to it.
+func newFakeAPI(t *testing.T) (*fakeAPI, *Client) {
+	f := &fakeAPI{t: t, routes: make(map[string]http.HandlerFunc), bodies: make(map[string][]byte)}
+	srv := httptest.NewServer(f)
+	uri := baseURI
+	baseURI = srv.URL + "/"
+	t.Cleanup(func() {
+		baseURI = uri
+		srv.Close()
+	})
+	c, err := New("test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, c
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := r.Method + " " + r.URL.Path
+	body, _ := ioutil.ReadAll(r.Body)
+	// The handler reads the body again.
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	f.mu.Lock()
+	f.calls = append(f.calls, r.Method+" "+r.URL.RequestURI())
+	f.bodies[route] = body
+	handler := f.routes[route]
+	key := r.Header.Get("X-DC-DEVKEY")
+	accepted := key == "test-key" || f.keys[key]
+	f.mu.Unlock()
+	if !accepted {
+		f.t.Errorf("%s was sent without the API key", route)
+	}
+	if handler == nil {
+		f.t.Errorf("unexpected call %s", route)
+		http.NotFound(w, r)
+		return
+	}
+	handler(w, r)
+}
+
+// acceptKey accepts key as an API key besides test-key.
+func (f *fakeAPI) acceptKey(key string) {
+	f.mu.Lock()
+	if f.keys == nil {
+		f.keys = make(map[string]bool)
+	}
+	f.keys[key] = true
+	f.mu.Unlock()
+}
+
+// handle registers handler for route.
+func (f *fakeAPI) handle(route string, handler http.HandlerFunc) {
+	f.mu.Lock()
+	f.routes[route] = handler
+	f.mu.Unlock()
+}
+
+// reply registers a fixed answer for route, a nil body answers no content.
+func (f *fakeAPI) reply(route string, status int, body interface{}) {
+	f.handle(route, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, status, body)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// count returns how many calls were made to route.
+func (f *fakeAPI) count(route string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, call := range f.calls {
+		if call == route || len(call) > len(route) && call[:len(route)+1] == route+"?" {
+			n++
+		}
+	}
+	return n
+}
+
+// body decodes the last request body sent to route into v.
+func (f *fakeAPI) body(route string, v interface{}) {
+	f.mu.Lock()
+	data := f.bodies[route]
+	f.mu.Unlock()
+	if err := json.Unmarshal(data, v); err != nil {
+		f.t.Fatalf("The body of %s is not JSON: %v, %s", route, err, data)
+	}
+}
+
+// requests returns the calls made so far as "METHOD /path?query".
+func (f *fakeAPI) requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func TestAPITime(t *testing.T) {
+	utc := time.Date(2019, 1, 1, 10, 11, 12, 0, time.UTC)
+	tests := []struct {
+		data string
+		want time.Time
+	}{
+		{`"2019-01-01T10:11:12.5Z"`, utc.Add(500 * time.Millisecond)},
+		{`"2019-01-01T10:11:12+00:00"`, utc},
+		{`"2019-01-01T10:11:12+0000"`, utc},
+		{`"2019-01-01T10:11:12"`, utc},
+		{`"2019-01-01 10:11:12+00:00"`, utc},
+		{`"2019-01-01 10:11:12+0000"`, utc},
+		{`"2019-01-01 10:11:12 +0000"`, utc},
+		{`"2019-01-01 12:11:12 +0200"`, utc},
+		{`"2019-01-01 10:11:12"`, utc},
+		{`" 2019-01-01 "`, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var dt DateTime
+		if err := json.Unmarshal([]byte(tt.data), &dt); err != nil || !dt.Equal(tt.want) {
+			t.Errorf("DateTime %s = %v, %v, want %v", tt.data, dt.Time, err, tt.want)
+		}
+		var d Date
+		if err := json.Unmarshal([]byte(tt.data), &d); err != nil || !d.Equal(tt.want) {
+			t.Errorf("Date %s = %v, %v, want %v", tt.data, d.Time, err, tt.want)
+		}
+	}
+	for _, data := range []string{`"01/01/2019"`, `"2019-01-01 10:11"`, `42`} {
+		var dt DateTime
+		if err := json.Unmarshal([]byte(data), &dt); err == nil {
+			t.Errorf("DateTime %s = %v, want an error", data, dt.Time)
+		}
+	}
+}
+
+func TestAPITimeMarshalJSON(t *testing.T) {
+	at := time.Date(2019, 1, 1, 10, 11, 12, 0, time.FixedZone("", 2*60*60))
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{DateTime{at}, `"2019-01-01T10:11:12+02:00"`},
+		{DateTime{}, `null`},
+		{Date{at}, `"2019-01-01"`},
+		{Date{}, `null`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.value)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("Marshal(%#v) = %s, %v, want %s", tt.value, data, err, tt.want)
+		}
+	}
+
+	// Both round-trip through their own encoding.
+	var dt DateTime
+	data, _ := json.Marshal(DateTime{at})
+	if err := json.Unmarshal(data, &dt); err != nil || !dt.Equal(at) {
+		t.Errorf("DateTime round-trip = %v, %v, want %v", dt.Time, err, at)
+	}
+	var d Date
+	data, _ = json.Marshal(Date{at})
+	if err := json.Unmarshal(data, &d); err != nil || d.Format("2006-01-02") != "2019-01-01" {
+		t.Errorf("Date round-trip = %v, %v", d.Time, err)
+	}
+	var zero struct {
+		DateCreated DateTime `json:"date_created"`
+		ValidTill   Date     `json:"valid_till"`
+	}
+	data, _ = json.Marshal(zero)
+	if err := json.Unmarshal(data, &zero); err != nil || !zero.DateCreated.IsZero() || !zero.ValidTill.IsZero() {
+		t.Errorf("zero round-trip of %s = %+v, %v", data, zero, err)
+	}
+}
+
+func TestDecodeAPITimes(t *testing.T) {
+	var order ViewOrderResponse
+	data := `{"id":1,"date_created":"2019-01-01 10:11:12 +0000","certificate":{"date_created":"2019-01-01T10:11:12+00:00","valid_from":"2019-01-02","valid_till":""}}`
+	if err := json.Unmarshal([]byte(data), &order); err != nil {
+		t.Fatal(err)
+	}
+	utc := time.Date(2019, 1, 1, 10, 11, 12, 0, time.UTC)
+	if !order.DateCreated.Equal(utc) || !order.Certificate.DateCreated.Equal(utc) {
+		t.Errorf("date_created = %v, %v", order.DateCreated.Time, order.Certificate.DateCreated.Time)
+	}
+	if order.Certificate.ValidFrom.Format("2006-01-02") != "2019-01-02" || !order.Certificate.ValidTill.IsZero() {
+		t.Errorf("valid_from = %v, valid_till = %v", order.Certificate.ValidFrom.Time, order.Certificate.ValidTill.Time)
+	}
+
+	var container ViewContainerDetails
+	if err := json.Unmarshal([]byte(`{"id":2,"name":"EMEA","date_created":"2019-01-01 10:11:12"}`), &container); err != nil || !container.DateCreated.Equal(utc) {
+		t.Errorf("ViewContainerDetails = %+v, %v", container, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":2,"date_created":null}`), &container); err != nil || !container.DateCreated.IsZero() {
+		t.Errorf("ViewContainerDetails = %+v, %v", container, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":2,"date_created":"yesterday"}`), &container); err == nil {
+		t.Error("ViewContainerDetails decoded a date_created of yesterday")
+	}
+}
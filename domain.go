@@ -2,40 +2,47 @@ package digicert
 
 import (
 	"encoding/json"
-	"time"
+	"errors"
+	"strconv"
 )
 
+// DomainValidation represents a validation type requested for a domain
+type DomainValidation struct {
+	Type string `json:"type"`
+	// User is the verified user of the validation, only sent when set.
+	User *IDReference `json:"user,omitempty"`
+}
+
 // NewDomainRequest represents a request of creating a new domain
 type NewDomainRequest struct {
-	Name         string `json:"name"`
-	Organization struct {
-		ID int `json:"id"`
-	} `json:"organization"`
-	Validations []struct {
-		Type string `json:"type"`
-		User struct {
-			ID int `json:"id"`
-		} `json:"user,omitempty"`
-	} `json:"validations"`
-	Dcv struct {
-		Method string `json:"method"`
+	Name         string             `json:"name"`
+	Organization IDReference        `json:"organization"`
+	Container    *IDReference       `json:"container,omitempty"`
+	Validations  []DomainValidation `json:"validations"`
+	Dcv          struct {
+		Method DCVMethod `json:"method"`
 	} `json:"dcv"`
 }
 
 // NewDomainResponse presents a response of creating a new domain
 type NewDomainResponse struct {
-	ID int `json:"id"`
+	ID       int `json:"id"`
+	DcvToken struct {
+		Token          string `json:"token"`
+		Status         string `json:"status"`
+		ExpirationDate Date   `json:"expiration_date"`
+	} `json:"dcv_token"`
 
 	SchemeValidationErrors
 }
 
 // ViewADomainResponse presents a domain detail, with dcv and validation
 type ViewADomainResponse struct {
-	ID           int       `json:"id"`
-	IsActive     bool      `json:"is_active"`
-	Status       string    `json:"status"`
-	Name         string    `json:"name"`
-	DateCreated  time.Time `json:"date_created"`
+	ID           int      `json:"id"`
+	IsActive     bool     `json:"is_active"`
+	Status       string   `json:"status"`
+	Name         string   `json:"name"`
+	DateCreated  DateTime `json:"date_created"`
 	Organization struct {
 		ID          int    `json:"id"`
 		Status      string `json:"status"`
@@ -47,10 +54,10 @@ type ViewADomainResponse struct {
 		Type           string    `json:"type"`
 		Name           string    `json:"name"`
 		Description    string    `json:"description"`
-		DateCreated    time.Time `json:"date_created"`
-		ValidatedUntil time.Time `json:"validated_until"`
+		DateCreated    DateTime  `json:"date_created"`
+		ValidatedUntil Date      `json:"validated_until"`
 		Status         string    `json:"status"`
-		DcvStatus      string    `json:"dcv_status"`
+		DcvStatus      DCVStatus `json:"dcv_status"`
 		OrgStatus      string    `json:"org_status"`
 		VerifiedUsers  []struct {
 			ID        int    `json:"id"`
@@ -69,29 +76,32 @@ type ViewADomainResponse struct {
 	SchemeValidationErrors
 }
 
+// ListedDomain presents a domain of ListDomains
+type ListedDomain struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	DateCreated  DateTime `json:"date_created"`
+	Organization struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		AssumedName string `json:"assumed_name"`
+		DisplayName string `json:"display_name"`
+	} `json:"organization"`
+	Validations []struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Status      string `json:"status"`
+	} `json:"validations,omitempty"`
+	Container struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"container"`
+}
+
 // ListDoaminsResponse presents all domains
 type ListDoaminsResponse struct {
-	Domains []struct {
-		ID           int       `json:"id"`
-		Name         string    `json:"name"`
-		DateCreated  time.Time `json:"date_created"`
-		Organization struct {
-			ID          int    `json:"id"`
-			Name        string `json:"name"`
-			AssumedName string `json:"assumed_name"`
-			DisplayName string `json:"display_name"`
-		} `json:"organization"`
-		Validations []struct {
-			Type        string `json:"type"`
-			Name        string `json:"name"`
-			Description string `json:"description"`
-			Status      string `json:"status"`
-		} `json:"validations,omitempty"`
-		Container struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		} `json:"container"`
-	} `json:"domains"`
+	Domains []ListedDomain `json:"domains"`
 
 	SchemeValidationErrors
 }
@@ -111,12 +121,7 @@ type ValidationTypesResponse struct {
 
 // ValidationRequest presents OV/EV validations
 type ValidationRequest struct {
-	Validations []struct {
-		Type string `json:"type"`
-		User struct {
-			ID int `json:"id"`
-		} `json:"user,omitempty"`
-	} `json:"validations"`
+	Validations []DomainValidation `json:"validations"`
 }
 
 // ViewValidationResponse presents a domain's validation detail
@@ -125,10 +130,10 @@ type ViewValidationResponse struct {
 		Type           string    `json:"type"`
 		Name           string    `json:"name"`
 		Description    string    `json:"description"`
-		DateCreated    time.Time `json:"date_created,omitempty"`
-		ValidatedUntil time.Time `json:"validated_until,omitempty"`
+		DateCreated    DateTime  `json:"date_created,omitempty"`
+		ValidatedUntil Date      `json:"validated_until,omitempty"`
 		Status         string    `json:"status"`
-		DcvStatus      string    `json:"dcv_status"`
+		DcvStatus      DCVStatus `json:"dcv_status"`
 		VerifiedUsers  []struct {
 			ID        int    `json:"id"`
 			FirstName string `json:"first_name"`
@@ -151,9 +156,9 @@ type ListDomainControlMethodsResponse struct {
 	SchemeValidationErrors
 }
 
-// DomainControlMethodRequest presents a request of domain control
+// DomainControlMethodRequest presents a request of domain control, as taken by the deprecated ChangeDomainControlMethod
 type DomainControlMethodRequest struct {
-	Method string `json:"method"`
+	Method DCVMethod `json:"method"`
 }
 
 // DomainControlEmailsResponse presents a domain control emails.
@@ -170,28 +175,61 @@ type ResendDCVEmailReqeust struct {
 	NameScope string `json:"name_scope"`
 }
 
+// DCVInvitation presents a DCV email invitation
+type DCVInvitation struct {
+	InvitationID int    `json:"invitation_id"`
+	Email        string `json:"email,omitempty"`
+}
+
 // EmailApprove presents submit email approve request.
 type EmailApprove struct {
-	Method         string `json:"method"`
-	NameScope      string `json:"name_scope"`
-	DcvInvitations []struct {
-		InvitationID int `json:"invitation_id"`
-	} `json:"dcv_invitations"`
+	Method         DCVMethod       `json:"method"`
+	NameScope      string          `json:"name_scope"`
+	DcvInvitations []DCVInvitation `json:"dcv_invitations"`
+}
+
+// SendDCVEmailsResponse presents the invitations sent for an email Domain Control Validation (DCV)
+type SendDCVEmailsResponse struct {
+	DcvInvitations []DCVInvitation `json:"dcv_invitations"`
+
+	SchemeValidationErrors
 }
 
 // DNSApprove presents submit dns approve request
 type DNSApprove struct {
-	Method string `json:"method"`
-	Token  string `json:"token"`
+	Method DCVMethod `json:"method"`
+	Token  string    `json:"token"`
+}
+
+// DomainDCVTokenRequest presents the DCV method sent by SetDomainDCVMethod and CheckDomainDCV
+type DomainDCVTokenRequest struct {
+	DcvMethod DCVMethod `json:"dcv_method"`
+}
+
+// DomainDCVTokenResponse presents the random value to publish for a token based domain control
+type DomainDCVTokenResponse struct {
+	DcvToken struct {
+		Token          string `json:"token"`
+		Status         string `json:"status"`
+		ExpirationDate Date   `json:"expiration_date"`
+	} `json:"dcv_token"`
+
+	SchemeValidationErrors
 }
 
 // ApproveStatuesResponse presents a status of approval process
 type ApproveStatuesResponse struct {
-	Status string `json:"status"`
+	Status    string    `json:"status"`
+	DcvStatus DCVStatus `json:"dcv_status"`
 
 	SchemeValidationErrors
 }
 
+// complete reports whether the response shows the domain control proven.
+func (r *ApproveStatuesResponse) complete() bool {
+	return r.DcvStatus == DCVStatusComplete
+}
+
 // NewDomain exports to add a domain for an organization in a container. You also must specify at least one validation type for the domain.
 func (c *Client) NewDomain(request *NewDomainRequest) (*NewDomainResponse, error) {
 	c.result = new(NewDomainResponse)
@@ -311,16 +349,69 @@ func (c *Client) ListDomainControlMethods() (*ListDomainControlMethodsResponse,
 }
 
 // ChangeDomainControlMethod exports Use this endpoint to set the Domain Control Validation (DCV) method for the domain.
+//
+// Deprecated: use SetDomainDCVMethod, which also returns the random value of the token based methods. It used to POST the method, which the API does not accept.
 func (c *Client) ChangeDomainControlMethod(domainID string, request *DomainControlMethodRequest) (bool, error) {
-	c.request = request
-	_, err := c.makeRequest("POST", "/domain/"+domainID+"/dcv/method", nil)
+	res, err := c.SetDomainDCVMethod(domainID, request.Method)
 	if err != nil {
 		return false, err
 	}
-	if c.statusCode == 200 {
-		return true, err
+	if err := res.err(); err != nil {
+		return false, err
 	}
-	return false, err
+	return true, nil
+}
+
+// SetDomainDCVMethod exports Use this endpoint to set the Domain Control Validation (DCV) method of the domain. For the token based methods, the response holds the random value to publish.
+func (c *Client) SetDomainDCVMethod(domainID string, method DCVMethod) (*DomainDCVTokenResponse, error) {
+	if !method.Known() {
+		return nil, errors.New("The wrong method")
+	}
+	c.result = new(DomainDCVTokenResponse)
+	c.request = &DomainDCVTokenRequest{
+		DcvMethod: method,
+	}
+	data, err := c.makeRequest("PUT", "/domain/"+domainID+"/dcv/method", nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.statusCode == 204 {
+		return c.result.(*DomainDCVTokenResponse), nil
+	}
+	if err := json.Unmarshal(data, &c.result); err != nil {
+		return nil, err
+	}
+	res := c.result.(*DomainDCVTokenResponse)
+	if c.statusCode >= 300 && res.err() == nil {
+		return nil, errors.New("The DCV method of domain " + domainID + " was not changed, status " + strconv.Itoa(c.statusCode))
+	}
+	return res, nil
+}
+
+// DomainDCVToken exports switches a domain to a token based Domain Control Validation (DCV) method with SetDomainDCVMethod and returns the random value to publish. Method: dns-txt-token, http-token
+func (c *Client) DomainDCVToken(domainID string, method DCVMethod) (*DomainDCVTokenResponse, error) {
+	switch method {
+	case DCVDNSTXTToken, DCVHTTPToken:
+	default:
+		return nil, errors.New("The wrong method")
+	}
+	return c.SetDomainDCVMethod(domainID, method)
+}
+
+// CheckDomainDCV exports Use this endpoint once the random value of DomainDCVToken is in place to have DigiCert check the token based Domain Control Validation (DCV) of the domain.
+func (c *Client) CheckDomainDCV(domainID string, method DCVMethod) (*ApproveStatuesResponse, error) {
+	c.result = new(ApproveStatuesResponse)
+	c.request = &DomainDCVTokenRequest{
+		DcvMethod: method,
+	}
+	data, err := c.makeRequest("PUT", "/domain/"+domainID+"/dcv/validate-token", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.result); err != nil {
+		return nil, err
+	}
+	return c.result.(*ApproveStatuesResponse), err
 }
 
 // GetDomainControlEmails exports Use this endpoint to retrieve domain email addresses for Domain Control Validation (DCV).
@@ -349,6 +440,23 @@ func (c *Client) ResendDCVEmail(domainID string, request *ResendDCVEmailReqeust)
 	return false, err
 }
 
+// SendDCVEmails exports Use this endpoint to send, or send again, the Domain Control Validation (DCV) emails of a domain for the given name scope and obtain the invitations sent when DigiCert returns them.
+func (c *Client) SendDCVEmails(domainID string, request *ResendDCVEmailReqeust) (*SendDCVEmailsResponse, error) {
+	c.result = new(SendDCVEmailsResponse)
+	c.request = request
+	data, err := c.makeRequest("POST", "/domain/"+domainID+"/dcv/emails", nil)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return c.result.(*SendDCVEmailsResponse), nil
+	}
+	if err := json.Unmarshal(data, &c.result); err != nil {
+		return nil, err
+	}
+	return c.result.(*SendDCVEmailsResponse), err
+}
+
 // ApproveEmail exports Use this endpoint to submit the Domain Control Validation (DCV) approval
 func (c *Client) ApproveEmail(domainID string, request *EmailApprove) (*ApproveStatuesResponse, error) {
 	c.result = new(ApproveStatuesResponse)
@@ -0,0 +1,234 @@
+package digicert
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DomainHealthKind presents the kind of a domain health event.
+type DomainHealthKind string
+
+// Domain health event kinds.
+const (
+	DomainValidationExpiring DomainHealthKind = "validation_expiring"
+	DomainValidationExpired  DomainHealthKind = "validation_expired"
+	DomainDCVPending         DomainHealthKind = "dcv_pending"
+	DomainDCVExpired         DomainHealthKind = "dcv_expired"
+	DomainInactiveInUse      DomainHealthKind = "inactive_in_use"
+)
+
+// DomainHealthEvent presents one finding of DomainHealth, suitable to forward to an alerting system.
+type DomainHealthEvent struct {
+	Kind           DomainHealthKind `json:"kind"`
+	Severity       string           `json:"severity"`
+	DomainID       int              `json:"domain_id"`
+	DomainName     string           `json:"domain_name"`
+	ContainerID    int              `json:"container_id"`
+	ValidationType string           `json:"validation_type,omitempty"`
+	ValidatedUntil *time.Time       `json:"validated_until,omitempty"`
+	DcvStatus      DCVStatus        `json:"dcv_status,omitempty"`
+	OrderIDs       []int            `json:"order_ids,omitempty"`
+}
+
+// DomainHealthReport presents the health of the domains of a container tree.
+type DomainHealthReport struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Containers  int                 `json:"containers"`
+	Domains     int                 `json:"domains"`
+	Events      []DomainHealthEvent `json:"events"`
+}
+
+// DomainHealth exports Use this to list, across rootContainerID and all its descendants, the domain validations expiring within the given window, the domains with a pending or failed DCV, and the inactive domains still referenced by issued or pending orders.
+// The domains are fetched concurrently within the limits of options, which may be nil.
+func (c *Client) DomainHealth(rootContainerID string, within time.Duration, options *ContainerTreeOptions) (*DomainHealthReport, error) {
+	concurrency, interval := containerTreeLimits(options)
+	tree, err := c.LoadContainerTree(rootContainerID, options)
+	if err != nil {
+		return nil, err
+	}
+	containers := tree.Nodes()
+	lists := make([]*ListDoaminsResponse, len(containers))
+	errs := make([]error, len(containers))
+	throttle(len(containers), concurrency, interval, func(i int) {
+		list, err := c.clone().ListDomains(strconv.Itoa(containers[i].ID))
+		if err == nil {
+			err = list.err()
+		}
+		lists[i], errs[i] = list, err
+	})
+	var ids []string
+	for i, list := range lists {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, d := range list.Domains {
+			ids = append(ids, strconv.Itoa(d.ID))
+		}
+	}
+	ids = uniqueStrings(ids)
+
+	domains := make([]*ViewADomainResponse, len(ids))
+	var mu sync.Mutex
+	var firstErr error
+	throttle(len(ids), concurrency, interval, func(i int) {
+		d, err := c.clone().ViewADomain(ids[i])
+		if err == nil {
+			err = d.err()
+		}
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			mu.Unlock()
+			return
+		}
+		domains[i] = d
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	now := time.Now()
+	report := &DomainHealthReport{
+		GeneratedAt: now,
+		Containers:  len(containers),
+		Domains:     len(domains),
+	}
+	names := make(map[string]int)
+	byID := make(map[int]*ViewADomainResponse)
+	anyInactive := false
+	for _, d := range domains {
+		byID[d.ID] = d
+		names[strings.ToLower(d.Name)] = d.ID
+		anyInactive = anyInactive || !d.IsActive
+		for _, v := range d.Validations {
+			e := DomainHealthEvent{
+				DomainID:       d.ID,
+				DomainName:     d.Name,
+				ContainerID:    d.Container.ID,
+				ValidationType: v.Type,
+				DcvStatus:      v.DcvStatus,
+			}
+			if !v.ValidatedUntil.IsZero() {
+				until := v.ValidatedUntil.Time
+				e.ValidatedUntil = &until
+			}
+			// The expiry and the DCV status are independent, a validation can report both.
+			expiry := e
+			switch {
+			case !v.ValidatedUntil.IsZero() && !v.ValidatedUntil.After(now):
+				expiry.Kind, expiry.Severity = DomainValidationExpired, "critical"
+			case !v.ValidatedUntil.IsZero() && v.ValidatedUntil.Before(now.Add(within)):
+				expiry.Kind, expiry.Severity = DomainValidationExpiring, "warning"
+			}
+			if expiry.Kind != "" {
+				report.Events = append(report.Events, expiry)
+			}
+			dcv := e
+			switch v.DcvStatus {
+			case DCVStatusPending:
+				dcv.Kind, dcv.Severity = DomainDCVPending, "warning"
+			case DCVStatusExpired:
+				dcv.Kind, dcv.Severity = DomainDCVExpired, "critical"
+			}
+			if dcv.Kind != "" {
+				report.Events = append(report.Events, dcv)
+			}
+		}
+	}
+
+	if anyInactive {
+		inUse := make(map[int][]int)
+		err := c.clone().eachOrderPage(func(page *ListOrders) bool {
+			for _, o := range page.Orders {
+				if o.Status != OrderIssued && o.Status != OrderPending {
+					continue
+				}
+				for _, name := range append([]string{o.Certificate.CommonName}, o.Certificate.DNSNames...) {
+					if _, id := coveringDomain(names, name); id != 0 && !byID[id].IsActive {
+						inUse[id] = append(inUse[id], o.ID)
+					}
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		for id, orders := range inUse {
+			d := byID[id]
+			report.Events = append(report.Events, DomainHealthEvent{
+				Kind:        DomainInactiveInUse,
+				Severity:    "critical",
+				DomainID:    d.ID,
+				DomainName:  d.Name,
+				ContainerID: d.Container.ID,
+				OrderIDs:    uniqueInts(orders),
+			})
+		}
+	}
+	sort.SliceStable(report.Events, func(i, j int) bool {
+		if report.Events[i].DomainName != report.Events[j].DomainName {
+			return report.Events[i].DomainName < report.Events[j].DomainName
+		}
+		return report.Events[i].Kind < report.Events[j].Kind
+	})
+	return report, nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *DomainHealthReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one line per event with a header line.
+func (r *DomainHealthReport) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"kind", "severity", "domain_id", "domain_name", "container_id", "validation_type", "validated_until", "dcv_status", "order_ids"})
+	for _, e := range r.Events {
+		var until string
+		if e.ValidatedUntil != nil {
+			until = e.ValidatedUntil.Format(time.RFC3339)
+		}
+		orders := make([]string, len(e.OrderIDs))
+		for i, id := range e.OrderIDs {
+			orders[i] = strconv.Itoa(id)
+		}
+		out.Write([]string{string(e.Kind), e.Severity, strconv.Itoa(e.DomainID), e.DomainName, strconv.Itoa(e.ContainerID), e.ValidationType, until, string(e.DcvStatus), strings.Join(orders, " ")})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func uniqueInts(list []int) []int {
+	seen := make(map[int]bool)
+	var out []int
+	for _, i := range list {
+		if !seen[i] {
+			seen[i] = true
+			out = append(out, i)
+		}
+	}
+	return out
+}
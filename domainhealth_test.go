@@ -0,0 +1,171 @@
+package digicert
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDomainHealthWalksContainerTree(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /container/1", 200, map[string]interface{}{"id": 1, "name": "Corp"})
+	api.reply("GET /container/1/children", 200, map[string]interface{}{"containers": []map[string]interface{}{{"id": 2, "name": "Web", "parent_id": 1}}})
+	// The child lists its parent again, which is not walked twice.
+	api.reply("GET /container/2/children", 200, map[string]interface{}{"containers": []map[string]interface{}{{"id": 1, "name": "Corp"}}})
+	api.handle("GET /domain", func(w http.ResponseWriter, r *http.Request) {
+		id := map[string]int{"1": 10, "2": 20}[r.URL.Query().Get("container_id")]
+		writeJSON(w, 200, map[string]interface{}{"domains": []map[string]interface{}{{"id": id}}})
+	})
+	api.reply("GET /domain/10", 200, map[string]interface{}{"id": 10, "name": "example.com", "is_active": true, "container": map[string]int{"id": 1}})
+	api.reply("GET /domain/20", 200, map[string]interface{}{
+		"id": 20, "name": "shop.example.com", "is_active": true, "container": map[string]int{"id": 2},
+		"validations": []map[string]string{{"type": "ov", "dcv_status": "pending"}},
+	})
+
+	report, err := c.DomainHealth("1", 30*24*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Containers != 2 || report.Domains != 2 {
+		t.Fatalf("report covers %d containers and %d domains", report.Containers, report.Domains)
+	}
+	if len(report.Events) != 1 || report.Events[0].Kind != DomainDCVPending || report.Events[0].ContainerID != 2 {
+		t.Errorf("Events = %+v", report.Events)
+	}
+	if n := api.count("GET /container/1/children"); n != 1 {
+		t.Errorf("The children of the root were listed %d times", n)
+	}
+}
+
+func TestDomainHealthEvents(t *testing.T) {
+	api, c := newFakeAPI(t)
+	soon := time.Now().Add(10 * 24 * time.Hour).UTC().Format("2006-01-02")
+	past := time.Now().Add(-24 * time.Hour).UTC().Format("2006-01-02")
+	api.reply("GET /container/1", 200, map[string]interface{}{"id": 1, "name": "Corp"})
+	api.reply("GET /container/1/children", 200, map[string]interface{}{"containers": []interface{}{}})
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]int{{"id": 10}, {"id": 11}, {"id": 12}, {"id": 13}}})
+	domain := func(id int, name string, active bool, validations ...map[string]string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": name, "is_active": active, "container": map[string]int{"id": 1}, "validations": validations}
+	}
+	api.reply("GET /domain/10", 200, domain(10, "example.com", true, map[string]string{"type": "ov", "validated_until": soon, "dcv_status": "complete"}))
+	api.reply("GET /domain/11", 200, domain(11, "old.example.com", true, map[string]string{"type": "ov", "validated_until": past, "dcv_status": "complete"}))
+	api.reply("GET /domain/12", 200, domain(12, "example.net", true, map[string]string{"type": "ev", "dcv_status": "expired"}))
+	api.reply("GET /domain/13", 200, domain(13, "legacy.example.org", false))
+	api.reply("GET /order/certificate/", 200, map[string]interface{}{
+		"orders": []map[string]interface{}{
+			// A subdomain of the inactive domain is covered by it.
+			{"id": 100, "status": "issued", "certificate": map[string]interface{}{"common_name": "www.legacy.example.org"}},
+			{"id": 101, "status": "canceled", "certificate": map[string]interface{}{"common_name": "legacy.example.org"}},
+			{"id": 102, "status": "pending", "certificate": map[string]interface{}{"common_name": "example.com", "dns_names": []string{"legacy.example.org"}}},
+		},
+		"page": map[string]int{"total": 3},
+	})
+
+	report, err := c.DomainHealth("1", 30*24*time.Hour, &ContainerTreeOptions{RateLimit{Interval: -1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type event struct {
+		kind     DomainHealthKind
+		severity string
+		domainID int
+		orderIDs []int
+	}
+	want := []event{
+		{DomainValidationExpiring, "warning", 10, nil},
+		{DomainDCVExpired, "critical", 12, nil},
+		{DomainInactiveInUse, "critical", 13, []int{100, 102}},
+		{DomainValidationExpired, "critical", 11, nil},
+	}
+	var got []event
+	for _, e := range report.Events {
+		got = append(got, event{e.Kind, e.Severity, e.DomainID, e.OrderIDs})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %+v, want %+v", got, want)
+	}
+	if until := report.Events[0].ValidatedUntil; until == nil || until.Format("2006-01-02") != soon {
+		t.Errorf("ValidatedUntil = %v, want %s", until, soon)
+	}
+
+	var csv bytes.Buffer
+	if err := report.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	wantLines := []string{
+		"kind,severity,domain_id,domain_name,container_id,validation_type,validated_until,dcv_status,order_ids",
+		"validation_expiring,warning,10,example.com,1,ov," + soon + "T00:00:00Z,complete,",
+		"dcv_expired,critical,12,example.net,1,ev,,expired,",
+		"inactive_in_use,critical,13,legacy.example.org,1,,,,100 102",
+		"validation_expired,critical,11,old.example.com,1,ov," + past + "T00:00:00Z,complete,",
+	}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("CSV =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(wantLines, "\n"))
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Containers int                      `json:"containers"`
+		Domains    int                      `json:"domains"`
+		Events     []map[string]interface{} `json:"events"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Containers != 1 || decoded.Domains != 4 || len(decoded.Events) != 4 {
+		t.Fatalf("JSON = %s", out.Bytes())
+	}
+	if _, ok := decoded.Events[1]["validated_until"]; ok {
+		t.Errorf("An event without validation end has validated_until: %v", decoded.Events[1])
+	}
+	if ids := decoded.Events[2]["order_ids"]; !reflect.DeepEqual(ids, []interface{}{100.0, 102.0}) {
+		t.Errorf("order_ids = %v", ids)
+	}
+}
+
+func TestDomainHealthExpiringWithPendingDCV(t *testing.T) {
+	api, c := newFakeAPI(t)
+	soon := time.Now().Add(10 * 24 * time.Hour).UTC().Format("2006-01-02")
+	past := time.Now().Add(-24 * time.Hour).UTC().Format("2006-01-02")
+	api.reply("GET /container/1", 200, map[string]interface{}{"id": 1, "name": "Corp"})
+	api.reply("GET /container/1/children", 200, map[string]interface{}{"containers": []interface{}{}})
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]int{{"id": 10}, {"id": 11}}})
+	api.reply("GET /domain/10", 200, map[string]interface{}{
+		"id": 10, "name": "example.com", "is_active": true, "container": map[string]int{"id": 1},
+		"validations": []map[string]string{{"type": "ov", "validated_until": soon, "dcv_status": "pending"}},
+	})
+	api.reply("GET /domain/11", 200, map[string]interface{}{
+		"id": 11, "name": "example.net", "is_active": true, "container": map[string]int{"id": 1},
+		"validations": []map[string]string{{"type": "ev", "validated_until": past, "dcv_status": "expired"}},
+	})
+
+	report, err := c.DomainHealth("1", 30*24*time.Hour, &ContainerTreeOptions{RateLimit{Interval: -1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type event struct {
+		kind     DomainHealthKind
+		domainID int
+	}
+	want := []event{
+		{DomainDCVPending, 10},
+		{DomainValidationExpiring, 10},
+		{DomainDCVExpired, 11},
+		{DomainValidationExpired, 11},
+	}
+	var got []event
+	for _, e := range report.Events {
+		got = append(got, event{e.Kind, e.DomainID})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v, want %+v", got, want)
+	}
+}
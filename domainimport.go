@@ -0,0 +1,311 @@
+package digicert
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DomainImportEntry presents a domain to add with the validations to submit it for.
+type DomainImportEntry struct {
+	Domain          string
+	OrganizationID  int
+	ValidationTypes []string
+	// DcvMethod is one of email, dns-txt-token, http-token, defaults to email.
+	DcvMethod DCVMethod
+}
+
+// DomainImportStatus presents what ImportDomains did with an entry.
+type DomainImportStatus string
+
+// Domain import statuses.
+const (
+	DomainImportCreated DomainImportStatus = "created"
+	DomainImportExists  DomainImportStatus = "exists"
+	// DomainImportDuplicate is an entry repeated in the input.
+	DomainImportDuplicate DomainImportStatus = "duplicate"
+	DomainImportFailed    DomainImportStatus = "failed"
+)
+
+// DomainImportResult presents the outcome of importing one entry, with what is left to do to complete the DCV.
+type DomainImportResult struct {
+	Entry     DomainImportEntry
+	Status    DomainImportStatus
+	DomainID  int
+	DcvEmails []string
+	DcvToken  string
+	Err       error
+}
+
+// DomainImportOptions presents how ImportDomains creates the missing domains.
+type DomainImportOptions struct {
+	// ContainerID is where the domains are looked up and created.
+	ContainerID string
+	RateLimit
+}
+
+// ImportDomains exports to add many domains at once, e.g. when onboarding a business unit.
+// Entries already present in the container (by ListDomains) or repeated in the input are skipped, the others are created concurrently and reported with the DCV emails or token still needing action.
+func (c *Client) ImportDomains(entries []DomainImportEntry, options *DomainImportOptions) ([]DomainImportResult, error) {
+	if options == nil || options.ContainerID == "" {
+		return nil, errors.New("The container of the domains must input")
+	}
+	concurrency, interval := options.limits()
+	list, err := c.clone().ListDomains(options.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := list.err(); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]int)
+	for _, d := range list.Domains {
+		existing[strings.ToLower(d.Name)] = d.ID
+	}
+	containerID, err := strconv.Atoi(options.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]DomainImportResult, len(entries))
+	var pending []int
+	seen := make(map[string]bool)
+	for i, e := range entries {
+		r := &results[i]
+		r.Entry = e
+		name := strings.ToLower(strings.TrimSpace(e.Domain))
+		switch {
+		case name == "":
+			r.Status, r.Err = DomainImportFailed, errors.New("The domain name must input")
+		case existing[name] != 0:
+			r.Status, r.DomainID = DomainImportExists, existing[name]
+		case seen[name]:
+			r.Status = DomainImportDuplicate
+		default:
+			seen[name] = true
+			pending = append(pending, i)
+		}
+	}
+
+	throttle(len(pending), concurrency, interval, func(p int) {
+		r := &results[pending[p]]
+		request := newDomainImportRequest(r.Entry, containerID)
+		cc := c.clone()
+		res, err := cc.NewDomain(request)
+		if err == nil {
+			err = res.err()
+		}
+		if err != nil {
+			r.Status, r.Err = DomainImportFailed, err
+			return
+		}
+		r.Status, r.DomainID, r.DcvToken = DomainImportCreated, res.ID, res.DcvToken.Token
+		if request.Dcv.Method == DCVEmail {
+			emails, err := cc.GetDomainControlEmails(strconv.Itoa(res.ID))
+			if err == nil {
+				err = emails.err()
+			}
+			if err != nil {
+				r.Err = err
+				return
+			}
+			r.DcvEmails = append(append(r.DcvEmails, emails.WhoisEmails...), emails.BaseEmails...)
+		}
+	})
+	return results, nil
+}
+
+func newDomainImportRequest(e DomainImportEntry, containerID int) *NewDomainRequest {
+	request := &NewDomainRequest{Name: strings.TrimSpace(e.Domain)}
+	request.Organization.ID = e.OrganizationID
+	request.Container = &IDReference{ID: containerID}
+	for _, t := range e.ValidationTypes {
+		request.Validations = append(request.Validations, DomainValidation{Type: t})
+	}
+	request.Dcv.Method = e.DcvMethod
+	if request.Dcv.Method == "" {
+		request.Dcv.Method = DCVEmail
+	}
+	return request
+}
+
+// ParseDomainImportCSV exports reads import entries from CSV with a header line naming the columns domain, organization_id, validations and dcv_method.
+// Several validation types are separated by spaces or semicolons.
+func ParseDomainImportCSV(r io.Reader) ([]DomainImportEntry, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["domain"]; !ok {
+		return nil, errors.New("The CSV has no domain column")
+	}
+	var entries []DomainImportEntry
+	for _, row := range rows[1:] {
+		fields := make(map[string]string)
+		for name, i := range columns {
+			if i < len(row) {
+				fields[name] = row[i]
+			}
+		}
+		e, err := newDomainImportEntry(fields)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// ParseDomainImportYAML exports reads import entries from a YAML list of mappings with the keys of ParseDomainImportCSV, e.g.
+//
+//	---
+//	- domain: example.com
+//	  organization_id: 112233
+//	  validations: [ov, ev]
+//	  dcv_method: dns-txt-token
+//	- domain: example.org
+//	  validations:
+//	    - ov
+//
+// The validations are a flow sequence, a block sequence or a plain list. Only this subset of YAML is understood: there are no anchors, multi-line strings or nested mappings.
+func ParseDomainImportYAML(r io.Reader) ([]DomainImportEntry, error) {
+	var entries []DomainImportEntry
+	var fields map[string]string
+	flush := func() error {
+		if fields == nil {
+			return nil
+		}
+		e, err := newDomainImportEntry(fields)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	}
+	// itemIndent is the indentation of the dash of the current entry, listKey the key whose block sequence may follow.
+	itemIndent, listKey := -1, ""
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := stripYAMLComment(strings.Replace(scanner.Text(), "\t", "    ", -1))
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		item := strings.HasPrefix(trimmed, "- ") || trimmed == "-"
+		if item && listKey != "" && indent > itemIndent {
+			fields[listKey] = strings.TrimSpace(fields[listKey] + " " + strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			continue
+		}
+		listKey = ""
+		if item {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			fields = make(map[string]string)
+			itemIndent = indent
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if trimmed == "" {
+				continue
+			}
+		}
+		i := strings.Index(trimmed, ":")
+		if fields == nil || i < 0 {
+			return nil, errors.New("The YAML line " + strconv.Itoa(n) + " is not a list item field")
+		}
+		key := strings.ToLower(strings.TrimSpace(trimmed[:i]))
+		value := strings.TrimSpace(trimmed[i+1:])
+		value = strings.Trim(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), " ")
+		fields[key] = value
+		if value == "" {
+			listKey = key
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// stripYAMLComment removes a comment, starting with a # after a space, that is not inside a quoted value.
+// A quote only opens a value at the start of a scalar, so the apostrophe of o'reilly.example is kept as is.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c != quote {
+				continue
+			}
+			// A single quote is escaped by doubling it.
+			if quote == '\'' && i+1 < len(line) && line[i+1] == '\'' {
+				i++
+				continue
+			}
+			quote = 0
+		case (c == '"' || c == '\'') && yamlScalarStart(line[:i]):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// yamlScalarStart reports whether a scalar starts after before: at the start of the line, after a key or a sequence dash followed by a space, or inside a flow sequence.
+func yamlScalarStart(before string) bool {
+	t := strings.TrimRight(before, " \t")
+	if t == "" {
+		return true
+	}
+	switch t[len(t)-1] {
+	case '[', '{', ',':
+		return true
+	case ':', '-':
+		return len(t) < len(before)
+	}
+	return false
+}
+
+// newDomainImportEntry builds an entry from the raw column values.
+func newDomainImportEntry(fields map[string]string) (DomainImportEntry, error) {
+	unquote := func(s string) string {
+		return strings.Trim(strings.TrimSpace(s), `"'`)
+	}
+	e := DomainImportEntry{
+		Domain:    unquote(fields["domain"]),
+		DcvMethod: DCVMethod(strings.ToLower(unquote(fields["dcv_method"]))),
+	}
+	if org := unquote(fields["organization_id"]); org != "" {
+		id, err := strconv.Atoi(org)
+		if err != nil {
+			return e, errors.New("The organization_id of " + e.Domain + " is not a number")
+		}
+		e.OrganizationID = id
+	}
+	for _, t := range strings.FieldsFunc(fields["validations"], func(r rune) bool {
+		return r == ' ' || r == ';' || r == ','
+	}) {
+		e.ValidationTypes = append(e.ValidationTypes, unquote(t))
+	}
+	switch e.DcvMethod {
+	case "", DCVEmail, DCVDNSTXTToken, DCVHTTPToken:
+	default:
+		return e, errors.New("The dcv_method of " + e.Domain + " is not accepted")
+	}
+	return e, nil
+}
@@ -0,0 +1,186 @@
+package digicert
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDomainImportYAML(t *testing.T) {
+	input := `---
+# onboarding
+- domain: example.com
+  organization_id: 112233
+  validations: [ov, ev]
+  dcv_method: dns-txt-token
+- domain: example.org
+  validations:
+    - ov
+    - "ev"
+  dcv_method: http-token
+-
+  domain: example.net
+  validations:
+  - dv
+`
+	entries, err := ParseDomainImportYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DomainImportEntry{
+		{Domain: "example.com", OrganizationID: 112233, ValidationTypes: []string{"ov", "ev"}, DcvMethod: DCVDNSTXTToken},
+		{Domain: "example.org", ValidationTypes: []string{"ov", "ev"}, DcvMethod: DCVHTTPToken},
+		{Domain: "example.net", ValidationTypes: []string{"dv"}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+
+	quoted, err := ParseDomainImportYAML(strings.NewReader("- domain: \"a #b.example.com\" # a comment\n  dcv_method: 'http-token' #c\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quoted) != 1 || quoted[0].Domain != "a #b.example.com" || quoted[0].DcvMethod != DCVHTTPToken {
+		t.Errorf("entries = %+v, want the quoted value kept whole", quoted)
+	}
+
+	if _, err := ParseDomainImportYAML(strings.NewReader("domain: example.com\n")); err == nil {
+		t.Error("A mapping outside of a list is accepted")
+	}
+}
+
+func TestStripYAMLComment(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{"domain: example.com # note", "domain: example.com "},
+		{"# comment", ""},
+		{"domain: o'reilly.example # note", "domain: o'reilly.example "},
+		{"domain: rock'n'roll.example #note", "domain: rock'n'roll.example "},
+		{`domain: "a #b.example.com" # note`, `domain: "a #b.example.com" `},
+		{"domain: 'it''s #1.example' # note", "domain: 'it''s #1.example' "},
+		{"  - 'ev' # note", "  - 'ev' "},
+		{"validations: [ov, 'ev #x'] # note", "validations: [ov, 'ev #x'] "},
+		{"domain: example.com#not-a-comment", "domain: example.com#not-a-comment"},
+	}
+	for _, tt := range tests {
+		if got := stripYAMLComment(tt.line); got != tt.want {
+			t.Errorf("stripYAMLComment(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	entries, err := ParseDomainImportYAML(strings.NewReader("- domain: o'reilly.example # note\n  dcv_method: email\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Domain != "o'reilly.example" {
+		t.Errorf("entries = %+v, want the comment stripped after the apostrophe", entries)
+	}
+}
+
+func TestParseDomainImportCSV(t *testing.T) {
+	input := "Domain, Organization_ID ,validations,dcv_method\n" +
+		"example.com,112233,ov ev,DNS-TXT-Token\n" +
+		"example.org,,ov;ev,\n" +
+		"\"example.net\",1,\"ov, dv\",http-token\n"
+	entries, err := ParseDomainImportCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DomainImportEntry{
+		{Domain: "example.com", OrganizationID: 112233, ValidationTypes: []string{"ov", "ev"}, DcvMethod: DCVDNSTXTToken},
+		{Domain: "example.org", ValidationTypes: []string{"ov", "ev"}},
+		{Domain: "example.net", OrganizationID: 1, ValidationTypes: []string{"ov", "dv"}, DcvMethod: DCVHTTPToken},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+
+	tests := []struct {
+		input, err string
+	}{
+		{"name,validations\nexample.com,ov\n", "no domain column"},
+		{"domain,organization_id\nexample.com,acme\n", "organization_id of example.com"},
+		{"domain,dcv_method\nexample.com,fax\n", "dcv_method of example.com"},
+		{"domain,validations\nexample.com,\"ov\n", "quote"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseDomainImportCSV(strings.NewReader(tt.input)); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseDomainImportCSV(%q) error = %v, want %q", tt.input, err, tt.err)
+		}
+	}
+	if entries, err := ParseDomainImportCSV(strings.NewReader("")); entries != nil || err != nil {
+		t.Errorf("ParseDomainImportCSV of nothing = %v, %v", entries, err)
+	}
+}
+
+func TestImportDomainsOmitsUnsetUser(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []interface{}{}})
+	api.reply("POST /domain", 201, map[string]interface{}{"id": 7, "dcv_token": map[string]string{"token": "token"}})
+	results, err := c.ImportDomains([]DomainImportEntry{{Domain: "example.com", ValidationTypes: []string{"ov"}, DcvMethod: DCVDNSTXTToken}}, &DomainImportOptions{ContainerID: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != DomainImportCreated || results[0].DcvToken != "token" {
+		t.Fatalf("result = %+v", results[0])
+	}
+	var body map[string]interface{}
+	api.body("POST /domain", &body)
+	validation := body["validations"].([]interface{})[0].(map[string]interface{})
+	if _, ok := validation["user"]; ok {
+		t.Errorf("The validation is sent with a user: %v", validation)
+	}
+}
+
+func TestImportDomains(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]interface{}{{"id": 3, "name": "example.com"}}})
+	api.handle("POST /domain", func(w http.ResponseWriter, r *http.Request) {
+		var request NewDomainRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		switch request.Name {
+		case "example.org":
+			writeJSON(w, 201, map[string]interface{}{"id": 7})
+		case "example.net":
+			writeJSON(w, 201, map[string]interface{}{"id": 8})
+		default:
+			writeJSON(w, 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_domain", "message": "Invalid domain."}}})
+		}
+	})
+	api.reply("GET /domain/7/dcv/emails", 200, map[string]interface{}{"whois_emails": []string{"admin@example.org"}, "base_emails": []string{"webmaster@example.org"}})
+	api.reply("GET /domain/8/dcv/emails", 200, map[string]interface{}{"errors": []map[string]string{{"code": "access_denied", "message": "Permission denied."}}})
+
+	entries := []DomainImportEntry{
+		{Domain: "Example.com"},
+		{Domain: "example.org", OrganizationID: 5, ValidationTypes: []string{"ov"}},
+		{Domain: "EXAMPLE.org"},
+		{Domain: "bad..example"},
+		{Domain: " "},
+		{Domain: "example.net"},
+	}
+	results, err := c.ImportDomains(entries, &DomainImportOptions{ContainerID: "1", RateLimit: RateLimit{Interval: -1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DomainImportStatus{DomainImportExists, DomainImportCreated, DomainImportDuplicate, DomainImportFailed, DomainImportFailed, DomainImportCreated}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%q: status %s, want %s", r.Entry.Domain, r.Status, want[i])
+		}
+	}
+	if results[0].DomainID != 3 {
+		t.Errorf("The existing domain is %d", results[0].DomainID)
+	}
+	if r := results[1]; r.DomainID != 7 || r.Err != nil || !reflect.DeepEqual(r.DcvEmails, []string{"admin@example.org", "webmaster@example.org"}) {
+		t.Errorf("example.org = %+v", r)
+	}
+	if r := results[5]; r.Err == nil {
+		t.Errorf("The DCV emails error of example.net is ignored: %+v", r)
+	}
+	if _, err := c.ImportDomains(entries, nil); err == nil {
+		t.Error("An import without container is accepted")
+	}
+}
@@ -0,0 +1,133 @@
+package digicert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDomainNotFound is returned when no domain record covers a name.
+var ErrDomainNotFound = errors.New("There is no domain covering the name")
+
+// FindDomain exports returns the most specific domain record of the container covering name: the name itself, else its closest parent up to the base domain. Wildcards are looked up by their base name.
+// Every call lists the domains of the container, use a DomainIndex to resolve many names.
+func (c *Client) FindDomain(ctx context.Context, name, containerID string) (*ListedDomain, error) {
+	index := c.NewDomainIndex(containerID, 0)
+	return index.Find(ctx, name)
+}
+
+// DomainIndex exports a cached name to domain index of a container, so order tooling can resolve domain IDs from hostnames.
+// The index is loaded on first use and reloaded on the next lookup once the refresh interval has elapsed, since the last load or the last failed attempt. It is safe for concurrent use.
+type DomainIndex struct {
+	client      *Client
+	containerID string
+	refresh     time.Duration
+
+	mu     sync.RWMutex
+	loaded time.Time
+	// failed is when the last reload failed, so an outage is not retried on every lookup.
+	failed  time.Time
+	domains []ListedDomain
+	names   map[string]int
+	// load is the reload in progress, shared by the concurrent lookups.
+	load *domainLoad
+}
+
+type domainLoad struct {
+	done chan struct{}
+	err  error
+	// canceled is set when the reload stopped with the context of its caller.
+	canceled bool
+}
+
+// NewDomainIndex exports creates an index of the domains of containerID, reloaded every refresh, a zero refresh never reloads.
+func (c *Client) NewDomainIndex(containerID string, refresh time.Duration) *DomainIndex {
+	return &DomainIndex{
+		client:      c.clone(),
+		containerID: containerID,
+		refresh:     refresh,
+	}
+}
+
+// Find returns the most specific domain record covering name, ErrDomainNotFound when there is none.
+// When a periodic reload fails the previous domains are searched, the error is only returned while nothing was ever loaded.
+func (x *DomainIndex) Find(ctx context.Context, name string) (*ListedDomain, error) {
+	x.mu.RLock()
+	last := x.loaded
+	if x.failed.After(last) {
+		last = x.failed
+	}
+	stale := x.names == nil || (x.refresh > 0 && time.Since(last) > x.refresh)
+	x.mu.RUnlock()
+	var err error
+	if stale {
+		err = x.Refresh(ctx)
+	}
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if err != nil && x.names == nil {
+		return nil, err
+	}
+	_, i := coveringDomain(x.names, name)
+	if i == 0 {
+		return nil, ErrDomainNotFound
+	}
+	d := x.domains[i-1]
+	return &d, nil
+}
+
+// Refresh reloads the domains of the container. Concurrent calls share a single reload, and each returns as soon as its ctx is done; the lookups keep using the previous domains meanwhile.
+func (x *DomainIndex) Refresh(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		x.mu.Lock()
+		load := x.load
+		leader := load == nil
+		if leader {
+			load = &domainLoad{done: make(chan struct{})}
+			x.load = load
+		}
+		x.mu.Unlock()
+		if leader {
+			x.reload(ctx, load)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-load.done:
+		}
+		// The reload of another caller stopped with its context, this one tries again.
+		if !load.canceled || leader {
+			return load.err
+		}
+	}
+}
+
+// reload lists the domains with ctx and publishes them, then ends load.
+func (x *DomainIndex) reload(ctx context.Context, load *domainLoad) {
+	list, err := x.client.withContext(ctx).ListDomains(x.containerID)
+	if err == nil {
+		err = list.err()
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load = nil
+	load.err, load.canceled = err, err != nil && ctx.Err() != nil
+	defer close(load.done)
+	if err != nil {
+		if !load.canceled {
+			x.failed = time.Now()
+		}
+		return
+	}
+	// names maps to the position in domains plus one, zero meaning absent as for coveringDomain.
+	names := make(map[string]int)
+	for i, d := range list.Domains {
+		names[strings.ToLower(d.Name)] = i + 1
+	}
+	x.domains, x.names, x.loaded = list.Domains, names, time.Now()
+}
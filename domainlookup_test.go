@@ -0,0 +1,162 @@
+package digicert
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDomainIndexSharesReload(t *testing.T) {
+	api, c := newFakeAPI(t)
+	release := make(chan struct{})
+	api.handle("GET /domain", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		writeJSON(w, 200, map[string]interface{}{"domains": []map[string]interface{}{
+			{"id": 1, "name": "example.com"},
+			{"id": 2, "name": "shop.example.com"},
+		}})
+	})
+	index := c.NewDomainIndex("1", time.Hour)
+
+	var wg sync.WaitGroup
+	found := make([]int, 8)
+	for i := range found {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d, err := index.Find(context.Background(), "www.shop.example.com")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			found[i] = d.ID
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, id := range found {
+		if id != 2 {
+			t.Fatalf("found = %v, want the shop.example.com domain", found)
+		}
+	}
+	if n := api.count("GET /domain"); n != 1 {
+		t.Errorf("The domains were listed %d times", n)
+	}
+	if _, err := index.Find(context.Background(), "example.org"); err != ErrDomainNotFound {
+		t.Errorf("Find(example.org) error = %v", err)
+	}
+}
+
+func TestDomainIndexCanceled(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("GET /domain", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Error("The request was not canceled with the context")
+		}
+	})
+	index := c.NewDomainIndex("1", 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := index.Find(ctx, "example.com"); err == nil {
+		t.Fatal("Find succeeded with a canceled context")
+	}
+
+	// The next lookup reloads.
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]interface{}{{"id": 1, "name": "example.com"}}})
+	if d, err := index.Find(context.Background(), "example.com"); err != nil || d.ID != 1 {
+		t.Fatalf("Find = %+v, %v", d, err)
+	}
+}
+
+func TestDomainIndexKeepsDomainsWhenReloadFails(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]interface{}{{"id": 1, "name": "example.com"}}})
+	index := c.NewDomainIndex("1", time.Millisecond)
+	if d, err := index.Find(context.Background(), "www.example.com"); err != nil || d.ID != 1 {
+		t.Fatalf("Find = %+v, %v", d, err)
+	}
+
+	api.reply("GET /domain", 200, map[string]interface{}{"errors": []map[string]string{{"code": "error", "message": "Unavailable."}}})
+	time.Sleep(5 * time.Millisecond)
+	if d, err := index.Find(context.Background(), "www.example.com"); err != nil || d.ID != 1 {
+		t.Fatalf("Find after a failed reload = %+v, %v, want the cached domain", d, err)
+	}
+	if n := api.count("GET /domain"); n != 2 {
+		t.Errorf("The domains were listed %d times, want a reload attempt", n)
+	}
+	if _, err := index.Find(context.Background(), "example.org"); err != ErrDomainNotFound {
+		t.Errorf("Find(example.org) error = %v, want ErrDomainNotFound", err)
+	}
+
+	// Without cached domains the error is returned.
+	empty := c.NewDomainIndex("1", 0)
+	if _, err := empty.Find(context.Background(), "example.com"); err == nil || err == ErrDomainNotFound {
+		t.Errorf("Find on the first load error = %v, want the API error", err)
+	}
+}
+
+func TestDomainIndexBacksOffAfterFailedReload(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []map[string]interface{}{{"id": 1, "name": "example.com"}}})
+	index := c.NewDomainIndex("1", 50*time.Millisecond)
+	if _, err := index.Find(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	api.reply("GET /domain", 200, map[string]interface{}{"errors": []map[string]string{{"code": "error", "message": "Unavailable."}}})
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if d, err := index.Find(context.Background(), "example.com"); err != nil || d.ID != 1 {
+			t.Fatalf("Find during the outage = %+v, %v", d, err)
+		}
+	}
+	if n := api.count("GET /domain"); n != 2 {
+		t.Errorf("The domains were listed %d times during the outage, want one attempt per refresh", n)
+	}
+	time.Sleep(60 * time.Millisecond)
+	index.Find(context.Background(), "example.com")
+	if n := api.count("GET /domain"); n != 3 {
+		t.Errorf("The domains were listed %d times, want another attempt after the refresh interval", n)
+	}
+}
+
+func TestFindDomain(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("GET /domain", func(w http.ResponseWriter, r *http.Request) {
+		if id := r.URL.Query().Get("container_id"); id != "3" {
+			writeJSON(w, 200, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_container", "message": "Invalid container."}}})
+			return
+		}
+		writeJSON(w, 200, map[string]interface{}{"domains": []map[string]interface{}{
+			{"id": 1, "name": "Example.com"},
+			{"id": 2, "name": "shop.example.com"},
+		}})
+	})
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"example.com", 1},
+		{"WWW.Example.com.", 1},
+		{"*.example.com", 1},
+		{"shop.example.com", 2},
+		{"cdn.shop.example.com", 2},
+		{"*.shop.example.com", 2},
+	}
+	for _, tt := range tests {
+		if d, err := c.FindDomain(context.Background(), tt.name, "3"); err != nil || d.ID != tt.want {
+			t.Errorf("FindDomain(%q) = %+v, %v, want domain %d", tt.name, d, err, tt.want)
+		}
+	}
+	if _, err := c.FindDomain(context.Background(), "example.org", "3"); err != ErrDomainNotFound {
+		t.Errorf("FindDomain(example.org) error = %v, want ErrDomainNotFound", err)
+	}
+	if _, err := c.FindDomain(context.Background(), "example.com", "4"); err == nil || err == ErrDomainNotFound {
+		t.Errorf("FindDomain in container 4 error = %v, want the API error", err)
+	}
+}
@@ -0,0 +1,274 @@
+package digicert
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// OrderStatus presents the status of an order or of its certificate.
+type OrderStatus string
+
+// Order statuses.
+const (
+	OrderNeedsCSR       OrderStatus = "needs_csr"
+	OrderNeedsApproval  OrderStatus = "needs_approval"
+	OrderPending        OrderStatus = "pending"
+	OrderProcessing     OrderStatus = "processing"
+	OrderReissuePending OrderStatus = "reissue_pending"
+	OrderWaitingPickup  OrderStatus = "waiting_pickup"
+	OrderIssued         OrderStatus = "issued"
+	OrderRejected       OrderStatus = "rejected"
+	OrderCanceled       OrderStatus = "canceled"
+	OrderRevoked        OrderStatus = "revoked"
+	OrderExpired        OrderStatus = "expired"
+)
+
+// RequestStatus presents the status of a request awaiting approval.
+type RequestStatus string
+
+// Request statuses.
+const (
+	RequestSubmitted RequestStatus = "submitted"
+	RequestPending   RequestStatus = "pending"
+	RequestApproved  RequestStatus = "approved"
+	RequestRejected  RequestStatus = "rejected"
+)
+
+// DCVMethod presents how the control of a domain is proven.
+type DCVMethod string
+
+// DCV methods.
+const (
+	DCVEmail         DCVMethod = "email"
+	DCVDNSTXTToken   DCVMethod = "dns-txt-token"
+	DCVDNSCNAMEToken DCVMethod = "dns-cname-token"
+	DCVHTTPToken     DCVMethod = "http-token"
+)
+
+// DCVStatus presents the dcv_status of a domain validation or of the DCV check of an order or a domain.
+type DCVStatus string
+
+// DCV statuses.
+const (
+	DCVStatusPending  DCVStatus = "pending"
+	DCVStatusComplete DCVStatus = "complete"
+	DCVStatusExpired  DCVStatus = "expired"
+)
+
+// ProductNameID presents a product, as in the order endpoints.
+type ProductNameID string
+
+// Product name IDs.
+const (
+	ProductSSL                        ProductNameID = "ssl"
+	ProductSSLPlus                    ProductNameID = "ssl_plus"
+	ProductSSLWildcard                ProductNameID = "ssl_wildcard"
+	ProductSSLMultiDomain             ProductNameID = "ssl_multi_domain"
+	ProductSSLEVPlus                  ProductNameID = "ssl_ev_plus"
+	ProductSSLEVMultiDomain           ProductNameID = "ssl_ev_multi_domain"
+	ProductSSLBasic                   ProductNameID = "ssl_basic"
+	ProductSSLEVBasic                 ProductNameID = "ssl_ev_basic"
+	ProductSSLCloudWildcard           ProductNameID = "ssl_cloud_wildcard"
+	ProductSSLDVGeoTrust              ProductNameID = "ssl_dv_geotrust"
+	ProductSSLDVRapidSSL              ProductNameID = "ssl_dv_rapidssl"
+	ProductPrivateSSLPlus             ProductNameID = "private_ssl_plus"
+	ProductPrivateSSLWildcard         ProductNameID = "private_ssl_wildcard"
+	ProductPrivateSSLMultiDomain      ProductNameID = "private_ssl_multi_domain"
+	ProductClientPremiumSHA           ProductNameID = "client_premium_sha"
+	ProductClientEmailSecurityPlus    ProductNameID = "client_email_security_plus"
+	ProductClientDigitalSignaturePlus ProductNameID = "client_digital_signature_plus"
+	ProductCodeSigning                ProductNameID = "code_signing"
+	ProductCodeSigningEV              ProductNameID = "code_signing_ev"
+	ProductDocumentSigningOrg1        ProductNameID = "document_signing_org_1"
+	ProductDocumentSigningOrg2        ProductNameID = "document_signing_org_2"
+)
+
+// SignatureHash presents the hash algorithm of a certificate signature.
+type SignatureHash string
+
+// Signature hashes.
+const (
+	SignatureSHA256 SignatureHash = "sha256"
+	SignatureSHA384 SignatureHash = "sha384"
+	SignatureSHA512 SignatureHash = "sha512"
+)
+
+// PaymentMethod presents how an order is paid.
+type PaymentMethod string
+
+// Payment methods.
+const (
+	PaymentBalance PaymentMethod = "balance"
+	PaymentCard    PaymentMethod = "card"
+	PaymentProfile PaymentMethod = "profile"
+)
+
+// Known reports whether s is one of the order statuses above.
+func (s OrderStatus) Known() bool {
+	switch s {
+	case OrderNeedsCSR, OrderNeedsApproval, OrderPending, OrderProcessing, OrderReissuePending, OrderWaitingPickup,
+		OrderIssued, OrderRejected, OrderCanceled, OrderRevoked, OrderExpired:
+		return true
+	}
+	return false
+}
+
+// Known reports whether s is one of the request statuses above.
+func (s RequestStatus) Known() bool {
+	switch s {
+	case RequestSubmitted, RequestPending, RequestApproved, RequestRejected:
+		return true
+	}
+	return false
+}
+
+// Known reports whether m is one of the DCV methods above.
+func (m DCVMethod) Known() bool {
+	switch m {
+	case DCVEmail, DCVDNSTXTToken, DCVDNSCNAMEToken, DCVHTTPToken:
+		return true
+	}
+	return false
+}
+
+// Known reports whether s is one of the DCV statuses above.
+func (s DCVStatus) Known() bool {
+	switch s {
+	case DCVStatusPending, DCVStatusComplete, DCVStatusExpired:
+		return true
+	}
+	return false
+}
+
+// Known reports whether p is one of the product name IDs above.
+func (p ProductNameID) Known() bool {
+	switch p {
+	case ProductSSL, ProductSSLPlus, ProductSSLWildcard, ProductSSLMultiDomain, ProductSSLEVPlus, ProductSSLEVMultiDomain,
+		ProductSSLBasic, ProductSSLEVBasic, ProductSSLCloudWildcard, ProductSSLDVGeoTrust, ProductSSLDVRapidSSL,
+		ProductPrivateSSLPlus, ProductPrivateSSLWildcard, ProductPrivateSSLMultiDomain,
+		ProductClientPremiumSHA, ProductClientEmailSecurityPlus, ProductClientDigitalSignaturePlus,
+		ProductCodeSigning, ProductCodeSigningEV, ProductDocumentSigningOrg1, ProductDocumentSigningOrg2:
+		return true
+	}
+	return false
+}
+
+// Known reports whether h is one of the signature hashes above.
+func (h SignatureHash) Known() bool {
+	switch h {
+	case SignatureSHA256, SignatureSHA384, SignatureSHA512:
+		return true
+	}
+	return false
+}
+
+// Known reports whether m is one of the payment methods above.
+func (m PaymentMethod) Known() bool {
+	switch m {
+	case PaymentBalance, PaymentCard, PaymentProfile:
+		return true
+	}
+	return false
+}
+
+// String returns the value as sent to the API.
+func (s OrderStatus) String() string {
+	return string(s)
+}
+
+// String returns the value as sent to the API.
+func (s RequestStatus) String() string {
+	return string(s)
+}
+
+// String returns the value as sent to the API.
+func (m DCVMethod) String() string {
+	return string(m)
+}
+
+// String returns the value as sent to the API.
+func (s DCVStatus) String() string {
+	return string(s)
+}
+
+// String returns the value as sent to the API.
+func (p ProductNameID) String() string {
+	return string(p)
+}
+
+// ValidationType returns the validation the product needs from the organization domains: ov, ev, or dv for the products validated per order. It is empty for the products without domain validation.
+func (p ProductNameID) ValidationType() string {
+	switch p {
+	case ProductSSL, ProductSSLPlus, ProductSSLWildcard, ProductSSLMultiDomain, ProductSSLCloudWildcard, ProductSSLBasic:
+		return "ov"
+	case ProductSSLEVPlus, ProductSSLEVMultiDomain, ProductSSLEVBasic:
+		return "ev"
+	case ProductSSLDVGeoTrust, ProductSSLDVRapidSSL:
+		return "dv"
+	}
+	return ""
+}
+
+// String returns the value as sent to the API.
+func (h SignatureHash) String() string {
+	return string(h)
+}
+
+// String returns the value as sent to the API.
+func (m PaymentMethod) String() string {
+	return string(m)
+}
+
+// UnmarshalJSON accepts any value, in any case.
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	*s = OrderStatus(unmarshalEnum(data))
+	return nil
+}
+
+// UnmarshalJSON accepts any value, in any case.
+func (s *RequestStatus) UnmarshalJSON(data []byte) error {
+	*s = RequestStatus(unmarshalEnum(data))
+	return nil
+}
+
+// UnmarshalJSON accepts any value, in any case.
+func (m *DCVMethod) UnmarshalJSON(data []byte) error {
+	*m = DCVMethod(unmarshalEnum(data))
+	return nil
+}
+
+// UnmarshalJSON accepts any value, in any case.
+func (s *DCVStatus) UnmarshalJSON(data []byte) error {
+	*s = DCVStatus(unmarshalEnum(data))
+	return nil
+}
+
+// UnmarshalJSON accepts any value, in any case.
+func (p *ProductNameID) UnmarshalJSON(data []byte) error {
+	*p = ProductNameID(unmarshalEnum(data))
+	return nil
+}
+
+// UnmarshalJSON accepts any value, in any case.
+func (h *SignatureHash) UnmarshalJSON(data []byte) error {
+	*h = SignatureHash(unmarshalEnum(data))
+	return nil
+}
+
+// UnmarshalJSON accepts any value, in any case.
+func (m *PaymentMethod) UnmarshalJSON(data []byte) error {
+	*m = PaymentMethod(unmarshalEnum(data))
+	return nil
+}
+
+// unmarshalEnum returns the lowercased value of a JSON string, or the raw JSON of any other value but null, so an unexpected value never fails the decoding of a whole response.
+func unmarshalEnum(data []byte) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		if raw := strings.TrimSpace(string(data)); raw != "null" {
+			return raw
+		}
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,101 @@
+package digicert
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumKnown(t *testing.T) {
+	tests := []struct {
+		name  string
+		known bool
+		want  bool
+	}{
+		{"issued", OrderIssued.Known(), true},
+		{"Issued", OrderStatus("Issued").Known(), false},
+		{"on_hold", OrderStatus("on_hold").Known(), false},
+		{"approved", RequestApproved.Known(), true},
+		{"", RequestStatus("").Known(), false},
+		{"dns-txt-token", DCVDNSTXTToken.Known(), true},
+		{"dns_txt_token", DCVMethod("dns_txt_token").Known(), false},
+		{"complete", DCVStatusComplete.Known(), true},
+		{"ssl_ev_basic", ProductSSLEVBasic.Known(), true},
+		{"document_signing_org_2", ProductDocumentSigningOrg2.Known(), true},
+		{"SSL_Plus", ProductNameID("SSL_Plus").Known(), false},
+		{"ssl_premium", ProductNameID("ssl_premium").Known(), false},
+		{"sha512", SignatureSHA512.Known(), true},
+		{"sha1", SignatureHash("sha1").Known(), false},
+		{"profile", PaymentProfile.Known(), true},
+		{"cash", PaymentMethod("cash").Known(), false},
+	}
+	for _, tt := range tests {
+		if tt.known != tt.want {
+			t.Errorf("Known(%q) = %v, want %v", tt.name, tt.known, tt.want)
+		}
+	}
+}
+
+func TestProductValidationType(t *testing.T) {
+	tests := []struct {
+		product ProductNameID
+		want    string
+	}{
+		{ProductSSL, "ov"},
+		{ProductSSLBasic, "ov"},
+		{ProductSSLMultiDomain, "ov"},
+		{ProductSSLCloudWildcard, "ov"},
+		{ProductSSLEVPlus, "ev"},
+		{ProductSSLEVMultiDomain, "ev"},
+		{ProductSSLEVBasic, "ev"},
+		{ProductSSLDVGeoTrust, "dv"},
+		{ProductSSLDVRapidSSL, "dv"},
+		{ProductPrivateSSLPlus, ""},
+		{ProductCodeSigningEV, ""},
+		{ProductNameID("unknown"), ""},
+	}
+	for _, tt := range tests {
+		if got := tt.product.ValidationType(); got != tt.want {
+			t.Errorf("%s.ValidationType() = %q, want %q", tt.product, got, tt.want)
+		}
+	}
+}
+
+func TestEnumUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`"issued"`, "issued"},
+		{`"Issued"`, "issued"},
+		{`" SSL_EV_Basic "`, "ssl_ev_basic"},
+		{`""`, ""},
+		{`null`, ""},
+		{`42`, "42"},
+		{`true`, "true"},
+		{`{"id":1}`, `{"id":1}`},
+	}
+	for _, tt := range tests {
+		var v struct {
+			Status  OrderStatus   `json:"status"`
+			Product ProductNameID `json:"product"`
+			Method  DCVMethod     `json:"method"`
+		}
+		data := `{"status":` + tt.data + `,"product":` + tt.data + `,"method":` + tt.data + `}`
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			t.Errorf("Unmarshal(%s) failed: %v", tt.data, err)
+			continue
+		}
+		if string(v.Status) != tt.want || string(v.Product) != tt.want || string(v.Method) != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, %q, %q, want %q", tt.data, v.Status, v.Product, v.Method, tt.want)
+		}
+	}
+
+	// A null keeps the decoding of the whole response going and leaves the value empty.
+	var order struct {
+		Status OrderStatus `json:"status"`
+		ID     int         `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(`{"status":null,"id":7}`), &order); err != nil || order.Status != "" || order.ID != 7 {
+		t.Errorf("Unmarshal = %+v, %v", order, err)
+	}
+}
@@ -12,7 +12,7 @@ func request() {
 		return
 	}
 
-	// req, err := c.ListRequests("")
+	// req, err := c.ListRequests(&digicert.RequestFilter{Status: digicert.RequestPending})
 	req, err := c.ViewRequest("3108958")
 
 	if err != nil {
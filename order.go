@@ -3,24 +3,23 @@ package digicert
 import (
 	"encoding/json"
 	"errors"
+	"regexp"
 	"strconv"
-	"strings"
-	"time"
 )
 
 // ViewOrderResponse exports view order detail
 type ViewOrderResponse struct {
 	ID          int `json:"id,omitempty"`
 	Certificate struct {
-		ID           int       `json:"id,omitempty"`
-		Thumbprint   string    `json:"thumbprint,omitempty"`
-		SerialNumber string    `json:"serial_number,omitempty"`
-		CommonName   string    `json:"common_name,omitempty"`
-		DNSNames     []string  `json:"dns_names,omitempty"`
-		DateCreated  time.Time `json:"date_created,omitempty"`
-		ValidFrom    string    `json:"valid_from,omitempty"`
-		ValidTill    string    `json:"valid_till,omitempty"`
-		Csr          string    `json:"csr,omitempty"`
+		ID           int      `json:"id,omitempty"`
+		Thumbprint   string   `json:"thumbprint,omitempty"`
+		SerialNumber string   `json:"serial_number,omitempty"`
+		CommonName   string   `json:"common_name,omitempty"`
+		DNSNames     []string `json:"dns_names,omitempty"`
+		DateCreated  DateTime `json:"date_created,omitempty"`
+		ValidFrom    Date     `json:"valid_from,omitempty"`
+		ValidTill    Date     `json:"valid_till,omitempty"`
+		Csr          string   `json:"csr,omitempty"`
 		Organization struct {
 			ID int `json:"id,omitempty"`
 		} `json:"organization,omitempty"`
@@ -31,18 +30,18 @@ type ViewOrderResponse struct {
 			InstallURL string `json:"install_url,omitempty"`
 			CsrURL     string `json:"csr_url,omitempty"`
 		} `json:"server_platform,omitempty"`
-		SignatureHash string `json:"signature_hash,omitempty"`
-		KeySize       int    `json:"key_size,omitempty"`
+		SignatureHash SignatureHash `json:"signature_hash,omitempty"`
+		KeySize       int           `json:"key_size,omitempty"`
 		CaCert        struct {
 			ID   string `json:"id,omitempty"`
 			Name string `json:"name,omitempty"`
 		} `json:"ca_cert,omitempty"`
 	} `json:"certificate,omitempty"`
-	Status         string    `json:"status,omitempty"`
-	IsRenewal      bool      `json:"is_renewal,omitempty"`
-	IsRenewed      bool      `json:"is_renewed,omitempty"`
-	RenewedOrderID int       `json:"renewed_order_id,omitempty"`
-	DateCreated    time.Time `json:"date_created,omitempty"`
+	Status         OrderStatus `json:"status,omitempty"`
+	IsRenewal      bool        `json:"is_renewal,omitempty"`
+	IsRenewed      bool        `json:"is_renewed,omitempty"`
+	RenewedOrderID int         `json:"renewed_order_id,omitempty"`
+	DateCreated    DateTime    `json:"date_created,omitempty"`
 	Organization   struct {
 		Name        string `json:"name,omitempty"`
 		DisplayName string `json:"display_name,omitempty"`
@@ -57,12 +56,12 @@ type ViewOrderResponse struct {
 		Name string `json:"name,omitempty"`
 	} `json:"container,omitempty"`
 	Product struct {
-		NameID                string `json:"name_id,omitempty"`
-		Name                  string `json:"name,omitempty"`
-		Type                  string `json:"type,omitempty"`
-		ValidationType        string `json:"validation_type,omitempty"`
-		ValidationName        string `json:"validation_name,omitempty"`
-		ValidationDescription string `json:"validation_description,omitempty"`
+		NameID                ProductNameID `json:"name_id,omitempty"`
+		Name                  string        `json:"name,omitempty"`
+		Type                  string        `json:"type,omitempty"`
+		ValidationType        string        `json:"validation_type,omitempty"`
+		ValidationName        string        `json:"validation_name,omitempty"`
+		ValidationDescription string        `json:"validation_description,omitempty"`
 	} `json:"product,omitempty"`
 	OrganizationContact struct {
 		FirstName string `json:"first_name,omitempty"`
@@ -83,11 +82,11 @@ type ViewOrderResponse struct {
 		Email     string `json:"email,omitempty"`
 	} `json:"user,omitempty"`
 	Requests []struct {
-		ID       int       `json:"id,omitempty"`
-		Date     time.Time `json:"date,omitempty"`
-		Type     string    `json:"type,omitempty"`
-		Status   string    `json:"status,omitempty"`
-		Comments string    `json:"comments,omitempty"`
+		ID       int           `json:"id,omitempty"`
+		Date     DateTime      `json:"date,omitempty"`
+		Type     string        `json:"type,omitempty"`
+		Status   RequestStatus `json:"status,omitempty"`
+		Comments string        `json:"comments,omitempty"`
 	} `json:"requests,omitempty"`
 	ReceiptID            int    `json:"receipt_id,omitempty"`
 	CsProvisioningMethod string `json:"cs_provisioning_method,omitempty"`
@@ -99,42 +98,45 @@ type ViewOrderResponse struct {
 		LastName  string `json:"last_name,omitempty"`
 		Email     string `json:"email,omitempty"`
 	} `json:"user_assignments,omitempty"`
-	PaymentMethod string `json:"payment_method,omitempty"`
-	DisableCt     bool   `json:"disable_ct,omitempty"`
+	PaymentMethod PaymentMethod `json:"payment_method,omitempty"`
+	DisableCt     bool          `json:"disable_ct,omitempty"`
 
 	SchemeValidationErrors
 }
 
+// ListedOrder exports an order of ListOrders
+type ListedOrder struct {
+	ID          int `json:"id,omitempty"`
+	Certificate struct {
+		ID            int           `json:"id,omitempty"`
+		CommonName    string        `json:"common_name,omitempty"`
+		DNSNames      []string      `json:"dns_names,omitempty"`
+		ValidTill     Date          `json:"valid_till,omitempty"`
+		SignatureHash SignatureHash `json:"signature_hash,omitempty"`
+	} `json:"certificate"`
+	Status       OrderStatus `json:"status,omitempty"`
+	DateCreated  DateTime    `json:"date_created,omitempty"`
+	Organization struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"organization"`
+	ValidityYears int `json:"validity_years,omitempty"`
+	Container     struct {
+		ID   int    `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"container"`
+	Product struct {
+		NameID ProductNameID `json:"name_id,omitempty"`
+		Name   string        `json:"name,omitempty"`
+		Type   string        `json:"type,omitempty"`
+	} `json:"product"`
+	Price int `json:"price,omitempty,omitempty"`
+}
+
 // ListOrders export listing all of orders
 type ListOrders struct {
-	Orders []struct {
-		ID          int `json:"id,omitempty"`
-		Certificate struct {
-			ID            int      `json:"id,omitempty"`
-			CommonName    string   `json:"common_name,omitempty"`
-			DNSNames      []string `json:"dns_names,omitempty"`
-			ValidTill     string   `json:"valid_till,omitempty"`
-			SignatureHash string   `json:"signature_hash,omitempty"`
-		} `json:"certificate"`
-		Status       string    `json:"status,omitempty"`
-		DateCreated  time.Time `json:"date_created,omitempty"`
-		Organization struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		} `json:"organization"`
-		ValidityYears int `json:"validity_years,omitempty"`
-		Container     struct {
-			ID   int    `json:"id,omitempty"`
-			Name string `json:"name,omitempty"`
-		} `json:"container"`
-		Product struct {
-			NameID string `json:"name_id,omitempty"`
-			Name   string `json:"name,omitempty"`
-			Type   string `json:"type,omitempty"`
-		} `json:"product"`
-		Price int `json:"price,omitempty,omitempty"`
-	} `json:"orders"`
-	Page struct {
+	Orders []ListedOrder `json:"orders"`
+	Page   struct {
 		Total  int `json:"total,omitempty"`
 		Limit  int `json:"limit,omitempty"`
 		Offset int `json:"offset,omitempty"`
@@ -156,6 +158,9 @@ func (c *Client) ViewOrder(orderID string) (*ViewOrderResponse, error) {
 	return c.result.(*ViewOrderResponse), err
 }
 
+// emptyOrganization matches the empty organization of ListOrders without touching the other empty arrays such as dns_names.
+var emptyOrganization = regexp.MustCompile(`"organization"\s*:\s*\[\s*\]`)
+
 // ListOrders exports Use this endpoint to retrieve a list of all certificate orders.
 func (c *Client) ListOrders(limit, offset int) (*ListOrders, error) {
 	c.result = new(ListOrders)
@@ -166,13 +171,30 @@ func (c *Client) ListOrders(limit, offset int) (*ListOrders, error) {
 		return nil, err
 	}
 
-	jsonByte := []byte(strings.Replace(string(data), "[]", "{}", -1)) // DigiCert API returns array [] as empty of Organization
+	jsonByte := emptyOrganization.ReplaceAll(data, []byte(`"organization":{}`)) // DigiCert API returns array [] as empty of Organization
 	if err := json.Unmarshal(jsonByte, &c.result); err != nil {
 		return nil, err
 	}
 	return c.result.(*ListOrders), err
 }
 
+// eachOrderPage calls fn with every page of ListOrders until fn returns false or the orders are exhausted.
+func (c *Client) eachOrderPage(fn func(page *ListOrders) bool) error {
+	const limit = 100
+	for offset := 0; ; offset += limit {
+		page, err := c.ListOrders(limit, offset)
+		if err != nil {
+			return err
+		}
+		if err := page.err(); err != nil {
+			return err
+		}
+		if !fn(page) || len(page.Orders) < limit || offset+limit >= page.Page.Total {
+			return nil
+		}
+	}
+}
+
 // submitting OV/EV/DV, Client certifiates orders to digicert
 
 // UnknownSSLRequest presents Order SSL Using Product Determinator
@@ -185,7 +207,7 @@ type UnknownSSLRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	RenewedThumbprint string `json:"renewed_thumbprint"`
 	Organization      struct {
@@ -206,8 +228,8 @@ type UnknownSSLRequest struct {
 type UnknownSSLResponse struct {
 	ID       int `json:"id"`
 	Requests []struct {
-		ID     int    `json:"id"`
-		Status string `json:"status"`
+		ID     int           `json:"id"`
+		Status RequestStatus `json:"status"`
 	} `json:"requests"`
 }
 
@@ -232,10 +254,10 @@ type OrderDVRequest struct {
 		JobTitle  string `json:"job_title"`
 		Telephone string `json:"telephone"`
 	} `json:"technical_contact"`
-	DisableCt          bool   `json:"disable_ct"`
-	DcvMethod          string `json:"dcv_method"`
-	Locale             string `json:"locale"`
-	AlternativeOrderID string `json:"alternative_order_id"`
+	DisableCt          bool      `json:"disable_ct"`
+	DcvMethod          DCVMethod `json:"dcv_method"`
+	Locale             string    `json:"locale"`
+	AlternativeOrderID string    `json:"alternative_order_id"`
 	DcvEmails          []struct {
 		DNSName string `json:"dns_name"`
 		Email   string `json:"email"`
@@ -271,9 +293,9 @@ func (c *Client) OrderDVSSL(dvBrand string, request *OrderDVRequest) (*OrderDVRe
 	c.request = request
 	switch dvBrand {
 	case "geotrust":
-		dvBrand = "ssl_dv_geotrust"
+		dvBrand = ProductSSLDVGeoTrust.String()
 	case "rapidssl":
-		dvBrand = "ssl_dv_rapidssl"
+		dvBrand = ProductSSLDVRapidSSL.String()
 	default:
 		return nil, errors.New("The DVSSL brands are not accepted")
 	}
@@ -296,27 +318,27 @@ type OrderStandardSSLRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		ProfileOption string `json:"profile_option"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		ProfileOption string        `json:"profile_option"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
 	} `json:"organization"`
-	ValidityYears               int    `json:"validity_years"`
-	CustomExpirationDate        string `json:"custom_expiration_date"`
-	Comments                    string `json:"comments"`
-	DisableRenewalNotifications bool   `json:"disable_renewal_notifications"`
-	RenewalOfOrderID            int    `json:"renewal_of_order_id"`
-	PaymentMethod               string `json:"payment_method"`
-	DisableCt                   bool   `json:"disable_ct"`
+	ValidityYears               int           `json:"validity_years"`
+	CustomExpirationDate        string        `json:"custom_expiration_date"`
+	Comments                    string        `json:"comments"`
+	DisableRenewalNotifications bool          `json:"disable_renewal_notifications"`
+	RenewalOfOrderID            int           `json:"renewal_of_order_id"`
+	PaymentMethod               PaymentMethod `json:"payment_method"`
+	DisableCt                   bool          `json:"disable_ct"`
 }
 
 // OrderOVEVSSLResponse presents a response of order digicert standard ssl
 type OrderOVEVSSLResponse struct {
 	ID       int `json:"id"`
 	Requests []struct {
-		ID     int    `json:"id"`
-		Status string `json:"status"`
+		ID     int           `json:"id"`
+		Status RequestStatus `json:"status"`
 	} `json:"requests"`
 }
 
@@ -344,8 +366,8 @@ type OrderSSLMultiDomainRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		ProfileOption string `json:"profile_option"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		ProfileOption string        `json:"profile_option"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -381,8 +403,8 @@ type OrderWildcardSSLRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		ProfileOption string `json:"profile_option"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		ProfileOption string        `json:"profile_option"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -418,7 +440,7 @@ type OrderEVSSLRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -455,8 +477,8 @@ type OrderEVMultiDomainRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		ProfileOption string `json:"profile_option"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		ProfileOption string        `json:"profile_option"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -493,7 +515,7 @@ type OrderCloudSSLReqeust struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -523,11 +545,11 @@ func (c *Client) OrderCloudSSL(request *OrderEVMultiDomainRequest) (*OrderOVEVSS
 // OrderClientPremiumRequest presents a requst of client premium order
 type OrderClientPremiumRequest struct {
 	Certificate struct {
-		CommonName        string   `json:"common_name"`
-		Emails            []string `json:"emails"`
-		Csr               string   `json:"csr"`
-		OrganizationUnits []string `json:"organization_units"`
-		SignatureHash     string   `json:"signature_hash"`
+		CommonName        string        `json:"common_name"`
+		Emails            []string      `json:"emails"`
+		Csr               string        `json:"csr"`
+		OrganizationUnits []string      `json:"organization_units"`
+		SignatureHash     SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -560,10 +582,10 @@ func (c *Client) OrderClientPremium(request *OrderClientPremiumRequest) (*OrderC
 // OrderClientEmailSecurityPlusRequest presents a request of client email security plus certificate
 type OrderClientEmailSecurityPlusRequest struct {
 	Certificate struct {
-		CommonName        string   `json:"common_name"`
-		Emails            []string `json:"emails"`
-		OrganizationUnits []string `json:"organization_units"`
-		SignatureHash     string   `json:"signature_hash"`
+		CommonName        string        `json:"common_name"`
+		Emails            []string      `json:"emails"`
+		OrganizationUnits []string      `json:"organization_units"`
+		SignatureHash     SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -576,11 +598,11 @@ type OrderClientEmailSecurityPlusRequest struct {
 // OrderClientDigitalSignaturePlusRequest presents a request of Order Client Digital Signature Plus
 type OrderClientDigitalSignaturePlusRequest struct {
 	Certificate struct {
-		CommonName        string   `json:"common_name"`
-		Emails            []string `json:"emails"`
-		Csr               string   `json:"csr"`
-		OrganizationUnits []string `json:"organization_units"`
-		SignatureHash     string   `json:"signature_hash"`
+		CommonName        string        `json:"common_name"`
+		Emails            []string      `json:"emails"`
+		Csr               string        `json:"csr"`
+		OrganizationUnits []string      `json:"organization_units"`
+		SignatureHash     SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -629,8 +651,8 @@ type OrderPrivateSSLPlusRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		CaCertID      string `json:"ca_cert_id"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		CaCertID      string        `json:"ca_cert_id"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -665,8 +687,8 @@ type OrderPrivateSSLWildcardRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		CaCertID      string `json:"ca_cert_id"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		CaCertID      string        `json:"ca_cert_id"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -702,8 +724,8 @@ type OrderPrivateSSLMultiDomainRequest struct {
 		ServerPlatform    struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
-		CaCertID      string `json:"ca_cert_id"`
+		SignatureHash SignatureHash `json:"signature_hash"`
+		CaCertID      string        `json:"ca_cert_id"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -736,7 +758,7 @@ type OrderCodeSigningRequest struct {
 		ServerPlatform struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
@@ -763,25 +785,16 @@ func (c *Client) OrderCodeSigning(request *OrderCodeSigningRequest) (*OrderOVEVS
 // OrderEVCodeSigningRequest presents a request of Order an EV Code Signing
 type OrderEVCodeSigningRequest struct {
 	Certificate struct {
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
 	} `json:"organization"`
-	ValidityYears        int    `json:"validity_years"`
-	Comments             string `json:"comments"`
-	RenewalOfOrderID     int    `json:"renewal_of_order_id"`
-	CsProvisioningMethod string `json:"cs_provisioning_method"`
-	ShipInfo             struct {
-		Name    string `json:"name"`
-		Addr1   string `json:"addr1"`
-		Addr2   string `json:"addr2"`
-		City    string `json:"city"`
-		State   string `json:"state"`
-		Zip     int    `json:"zip"`
-		Country string `json:"country"`
-		Method  string `json:"method"`
-	} `json:"ship_info"`
+	ValidityYears        int      `json:"validity_years"`
+	Comments             string   `json:"comments"`
+	RenewalOfOrderID     int      `json:"renewal_of_order_id"`
+	CsProvisioningMethod string   `json:"cs_provisioning_method"`
+	ShipInfo             ShipInfo `json:"ship_info"`
 }
 
 // OrderEVCodeSigning exports To order DigiCert EV code signing certificate.
@@ -790,6 +803,11 @@ type OrderEVCodeSigningRequest struct {
 // method	Required	STANDARD,EXPEDITED
 // The method to ship by, EXPEDITED carries an additional cost
 func (c *Client) OrderEVCodeSigning(request *OrderEVCodeSigningRequest) (*OrderOVEVSSLResponse, error) {
+	if request.CsProvisioningMethod == "ship_token" {
+		if err := request.ShipInfo.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	c.result = new(OrderOVEVSSLResponse)
 	c.request = request
 	data, err := c.makeRequest("POST", "/order/certificate/code_signing_ev", nil)
@@ -808,25 +826,16 @@ type OrderDocumentSigningOrganizationRequest struct {
 		ServerPlatform struct {
 			ID int `json:"id"`
 		} `json:"server_platform"`
-		SignatureHash string `json:"signature_hash"`
+		SignatureHash SignatureHash `json:"signature_hash"`
 	} `json:"certificate"`
 	Organization struct {
 		ID int `json:"id"`
 	} `json:"organization"`
-	ValidityYears        int    `json:"validity_years"`
-	Comments             string `json:"comments"`
-	CsProvisioningMethod string `json:"cs_provisioning_method"`
-	ShipInfo             struct {
-		Name    string `json:"name"`
-		Addr1   string `json:"addr1"`
-		Addr2   string `json:"addr2"`
-		City    string `json:"city"`
-		State   string `json:"state"`
-		Zip     int    `json:"zip"`
-		Country string `json:"country"`
-		Method  string `json:"method"`
-	} `json:"ship_info"`
-	Subject struct {
+	ValidityYears        int      `json:"validity_years"`
+	Comments             string   `json:"comments"`
+	CsProvisioningMethod string   `json:"cs_provisioning_method"`
+	ShipInfo             ShipInfo `json:"ship_info"`
+	Subject              struct {
 		Name     string `json:"name"`
 		JobTitle string `json:"job_title"`
 		Phone    string `json:"phone"`
@@ -840,12 +849,17 @@ type OrderDocumentSigningOrganizationRequest struct {
 // method	Required	STANDARD,EXPEDITED
 // The method to ship by, EXPEDITED carries an additional cost
 func (c *Client) OrderDocumentSigningOrganization(amount int, request *OrderDocumentSigningOrganizationRequest) (*OrderOVEVSSLResponse, error) {
+	if request.CsProvisioningMethod == "ship_token" {
+		if err := request.ShipInfo.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	var product string
 	switch amount {
 	case 2000:
-		product = "document_signing_org_1"
+		product = ProductDocumentSigningOrg1.String()
 	case 5000:
-		product = "document_signing_org_2"
+		product = ProductDocumentSigningOrg2.String()
 	default:
 		return nil, errors.New("There's no a product available for this amount")
 	}
@@ -1,107 +1,98 @@
 package digicert
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
 
 // NewOrganizationRequest represents that creating new organization in CertCentral.
 type NewOrganizationRequest struct {
-	Address     string `json:"address"`
-	Address2    string `json:"address2"`
+	Address
 	AssumedName string `json:"assumed_name"`
-	City        string `json:"city"`
-	// Container   struct {
-	// 	ID int `json:"id"`
-	// } `json:"container"`
-	Country             string  `json:"country"`
+	// Container is the container of the organization, defaults to the container of the API key user.
+	Container *IDReference `json:"container,omitempty"`
 	Name                string  `json:"name"`
 	OrganizationContact Contact `json:"organization_contact"`
-	State               string  `json:"state"`
-	Telephone           int     `json:"telephone"`
-	Zip                 int     `json:"zip"`
+	Telephone           string  `json:"telephone"`
 }
 
 // Contact exports contact stuct to new organization
 type Contact struct {
+	// ID is set on the contact of a viewed organization.
+	ID                 int    `json:"id,omitempty"`
 	Email              string `json:"email"`
 	FirstName          string `json:"first_name"`
 	JobTitle           string `json:"job_title"`
 	LastName           string `json:"last_name"`
-	Telephone          int    `json:"telephone"`
-	TelephoneExtension int    `json:"telephone_extension"`
+	Telephone          string `json:"telephone"`
+	TelephoneExtension string `json:"telephone_extension,omitempty"`
+}
+
+// OrganizationValidation exports a validation of an organization
+type OrganizationValidation struct {
+	DateCreated    DateTime `json:"date_created,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	Type           string   `json:"type,omitempty"`
+	ValidatedUntil Date     `json:"validated_until,omitempty"`
 }
 
 // ViewOrganizationValidationResponse exports organization valiation status
 type ViewOrganizationValidationResponse struct {
-	Validations []struct {
-		DateCreated    string `json:"date_created,omitempty"`
-		Description    string `json:"description,omitempty"`
-		Name           string `json:"name,omitempty"`
-		Status         string `json:"status,omitempty"`
-		Type           string `json:"type,omitempty"`
-		ValidatedUntil string `json:"validated_until,omitempty"`
-	} `json:"validations,omitempty"`
+	Validations []OrganizationValidation `json:"validations,omitempty"`
 
 	SchemeValidationErrors
 }
 
 // ViewOrganizationDetails represents the organization details
 type ViewOrganizationDetails struct {
-	Address     string `json:"address,omitempty"`
-	Address2    string `json:"address2,omitempty"`
+	Address
 	AssumedName string `json:"assumed_name,omitempty"`
-	City        string `json:"city,omitempty"`
 	Container   struct {
 		ID       int    `json:"id,omitempty"`
 		IsActive bool   `json:"is_active,omitempty"`
 		Name     string `json:"name,omitempty"`
 	} `json:"container,omitempty"`
-	Country     string `json:"country,omitempty"`
 	DisplayName string `json:"display_name,omitempty"`
 	EvApprovers []struct {
 		FirstName string `json:"first_name,omitempty"`
 		ID        int    `json:"id,omitempty"`
 		LastName  string `json:"last_name,omitempty"`
 	} `json:"ev_approvers,omitempty"`
-	ID                  int    `json:"id,omitempty"`
-	IsActive            bool   `json:"is_active,omitempty"`
-	Name                string `json:"name,omitempty"`
-	OrganizationContact struct {
-		Email              string `json:"email,omitempty"`
-		FirstName          string `json:"first_name,omitempty"`
-		ID                 int    `json:"id,omitempty"`
-		LastName           string `json:"last_name,omitempty"`
-		Telephone          string `json:"telephone,omitempty"`
-		TelephoneExtension string `json:"telephone_extension,omitempty"`
-	} `json:"organization_contact,omitempty"`
-	State     string `json:"state,omitempty"`
-	Status    string `json:"status,omitempty"`
-	Telephone string `json:"telephone,omitempty"`
-	Zip       string `json:"zip,omitempty"`
+	ID                  int     `json:"id,omitempty"`
+	IsActive            bool    `json:"is_active,omitempty"`
+	Name                string  `json:"name,omitempty"`
+	OrganizationContact Contact `json:"organization_contact,omitempty"`
+	Status              string  `json:"status,omitempty"`
+	Telephone           string  `json:"telephone,omitempty"`
 
 	SchemeValidationErrors
 }
 
+// ListedOrganization exports an organization of ListAllOrganizations
+type ListedOrganization struct {
+	Address
+	AssumedName string `json:"assumed_name,omitempty"`
+	Container   struct {
+		ID       int    `json:"id,omitempty"`
+		IsActive bool   `json:"is_active,omitempty"`
+		Name     string `json:"name,omitempty"`
+		ParentID int    `json:"parent_id,omitempty"`
+	} `json:"container,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	ID          int    `json:"id,omitempty"`
+	IsActive    bool   `json:"is_active,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Telephone   string `json:"telephone,omitempty"`
+}
+
 // AllOrganizationsResponse exports all organizations
 type AllOrganizationsResponse struct {
-	Organizations []struct {
-		Address   string `json:"address,omitempty"`
-		City      string `json:"city,omitempty"`
-		Container struct {
-			ID       int    `json:"id,omitempty"`
-			IsActive bool   `json:"is_active,omitempty"`
-			Name     string `json:"name,omitempty"`
-			ParentID int    `json:"parent_id,omitempty"`
-		} `json:"container,omitempty"`
-		Country     string `json:"country,omitempty"`
-		DisplayName string `json:"display_name,omitempty"`
-		ID          int    `json:"id,omitempty"`
-		IsActive    bool   `json:"is_active,omitempty"`
-		Name        string `json:"name,omitempty"`
-		State       string `json:"state,omitempty"`
-		Status      string `json:"status,omitempty"`
-		Telephone   string `json:"telephone,omitempty"`
-		Zip         string `json:"zip,omitempty"`
-	} `json:"organizations,omitempty"`
-	Page struct {
+	Organizations []ListedOrganization `json:"organizations,omitempty"`
+	Page          struct {
 		Limit  int `json:"limit,omitempty"`
 		Offset int `json:"offset,omitempty"`
 		Total  int `json:"total,omitempty"`
@@ -148,6 +139,9 @@ func (c *Client) ListAllOrganizations() (*AllOrganizationsResponse, error) {
 
 // NewOrganization exports Use this endpoint to create a new organization. The organization information will be used by DigiCert for validation and may appear on certificates.
 func (c *Client) NewOrganization(request *NewOrganizationRequest) (*ViewOrganizationDetails, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
 	c.result = new(ViewOrganizationDetails)
 	c.request = request
 	data, err := c.makeRequest("POST", "/organization/", nil)
@@ -174,16 +168,144 @@ func (c *Client) ViewOrganizationValidation(orgID string) (*ViewOrganizationVali
 }
 
 // ValidateOrganization exports Use this endpoint to submit an organization to DigiCert for the specified validations.
+//
+// Deprecated: use SubmitOrganizationValidation, which returns why the organization was not submitted.
 func (c *Client) ValidateOrganization(orgID string, request *ValidateOrganizationRequest) bool {
-	c.result = new(ViewOrganizationValidationResponse)
+	res, _ := c.SubmitOrganizationValidation(orgID, request)
+	return res != nil && res.Submitted
+}
+
+// OrganizationValidationResult exports the outcome of SubmitOrganizationValidation
+type OrganizationValidationResult struct {
+	Submitted  bool
+	StatusCode int
+}
+
+// SubmitOrganizationValidation exports Use this endpoint to submit an organization to DigiCert for the specified validations.
+// The result is returned along with the error when DigiCert answered the call.
+func (c *Client) SubmitOrganizationValidation(orgID string, request *ValidateOrganizationRequest) (*OrganizationValidationResult, error) {
+	c.request = request
+	data, err := c.makeRequest("POST", "/organization/"+orgID+"/validation", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrganizationValidationResult{StatusCode: c.statusCode}
+	if c.statusCode == 204 {
+		result.Submitted = true
+		return result, nil
+	}
+	var res SchemeValidationErrors
+	if err := json.Unmarshal(data, &res); err != nil {
+		return result, err
+	}
+	if err := res.err(); err != nil {
+		return result, err
+	}
+	return result, errors.New("The organization was not submitted for validation, status " + strconv.Itoa(c.statusCode))
+}
+
+// UpdateOrganizationRequest represents the organization details to update
+type UpdateOrganizationRequest struct {
+	Name        string `json:"name,omitempty"`
+	AssumedName string `json:"assumed_name,omitempty"`
+	Address
+	Telephone string `json:"telephone,omitempty"`
+}
+
+// OrganizationContactResponse represents the ID of an added organization contact
+type OrganizationContactResponse struct {
+	ID int `json:"id"`
+
+	SchemeValidationErrors
+}
+
+// EVApproversRequest represents the users to add as EV approvers of an organization
+type EVApproversRequest struct {
+	EvApprovers []IDReference `json:"ev_approvers"`
+}
+
+// UpdateOrganization exports Use this endpoint to update the details of an organization. Changing the name or address of a validated organization may require it to be validated again.
+func (c *Client) UpdateOrganization(orgID string, request *UpdateOrganizationRequest) (bool, error) {
+	if err := request.Validate(); err != nil {
+		return false, err
+	}
+	c.request = request
+	data, err := c.makeRequest("PUT", "/organization/"+orgID, nil)
+	return c.organizationResult(data, err)
+}
+
+// ActiveOrganization exports Use this endpoint to activate an organization that was previously deactivated.
+func (c *Client) ActiveOrganization(orgID string) (bool, error) {
+	data, err := c.makeRequest("PUT", "/organization/"+orgID+"/activate", nil)
+	return c.organizationResult(data, err)
+}
+
+// DeactiveOrganization exports Use this endpoint to deactivate an organization, it can no longer be used on new orders.
+func (c *Client) DeactiveOrganization(orgID string) (bool, error) {
+	data, err := c.makeRequest("PUT", "/organization/"+orgID+"/deactivate", nil)
+	return c.organizationResult(data, err)
+}
+
+// UpdateOrganizationContact exports Use this endpoint to replace the organization contact of an organization.
+func (c *Client) UpdateOrganizationContact(orgID string, request *Contact) (bool, error) {
+	if err := request.Validate(); err != nil {
+		return false, err
+	}
 	c.request = request
-	_, err := c.makeRequest("POST", "/organization/"+orgID+"/validation", nil)
+	data, err := c.makeRequest("PUT", "/organization/"+orgID+"/contact", nil)
+	return c.organizationResult(data, err)
+}
+
+// AddOrganizationContact exports Use this endpoint to add an additional contact to an organization.
+func (c *Client) AddOrganizationContact(orgID string, request *Contact) (*OrganizationContactResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	c.result = new(OrganizationContactResponse)
+	c.request = request
+	data, err := c.makeRequest("POST", "/organization/"+orgID+"/contact", nil)
 	if err != nil {
-		return false
+		return nil, err
 	}
+	if err := json.Unmarshal(data, &c.result); err != nil {
+		return nil, err
+	}
+	return c.result.(*OrganizationContactResponse), err
+}
+
+// RemoveOrganizationContact exports Use this endpoint to remove an additional contact from an organization.
+func (c *Client) RemoveOrganizationContact(orgID, contactID string) (bool, error) {
+	data, err := c.makeRequest("DELETE", "/organization/"+orgID+"/contact/"+contactID, nil)
+	return c.organizationResult(data, err)
+}
 
+// AddEVApprovers exports Use this endpoint to allow users to approve EV orders of an organization. The users need a job title and telephone.
+func (c *Client) AddEVApprovers(orgID string, request *EVApproversRequest) (bool, error) {
+	c.request = request
+	data, err := c.makeRequest("POST", "/organization/"+orgID+"/ev-approver", nil)
+	return c.organizationResult(data, err)
+}
+
+// RemoveEVApprover exports Use this endpoint to remove a user from the EV approvers of an organization.
+func (c *Client) RemoveEVApprover(orgID, userID string) (bool, error) {
+	data, err := c.makeRequest("DELETE", "/organization/"+orgID+"/ev-approver/"+userID, nil)
+	return c.organizationResult(data, err)
+}
+
+// organizationResult turns the outcome of an organization call answering 204 into (true, nil), else returns the error reported by the API.
+func (c *Client) organizationResult(data []byte, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
 	if c.statusCode == 204 {
-		return true
+		return true, nil
+	}
+	var res SchemeValidationErrors
+	if err := json.Unmarshal(data, &res); err == nil {
+		if err := res.err(); err != nil {
+			return false, err
+		}
 	}
-	return false
+	return false, errors.New("The organization was not changed, status " + strconv.Itoa(c.statusCode))
 }
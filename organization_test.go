@@ -0,0 +1,199 @@
+package digicert
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateOrganization(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /organization/3", 204, nil)
+	ok, err := c.UpdateOrganization("3", &UpdateOrganizationRequest{Address: Address{City: "Lehi"}, Telephone: "+1 801 555 0100"})
+	if err != nil || !ok {
+		t.Fatalf("UpdateOrganization = %v, %v", ok, err)
+	}
+	var body map[string]interface{}
+	api.body("PUT /organization/3", &body)
+	want := map[string]interface{}{"city": "Lehi", "telephone": "+1 801 555 0100"}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want only the updated fields %v", body, want)
+	}
+
+	if _, err := c.UpdateOrganization("3", &UpdateOrganizationRequest{Address: Address{Country: "usa"}}); err == nil {
+		t.Error("An invalid country is sent")
+	}
+	if n := api.count("PUT /organization/3"); n != 1 {
+		t.Errorf("The organization was updated %d times", n)
+	}
+}
+
+func TestOrganizationStatus(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /organization/3/activate", 204, nil)
+	api.reply("PUT /organization/3/deactivate", 204, nil)
+	if ok, err := c.ActiveOrganization("3"); err != nil || !ok {
+		t.Errorf("ActiveOrganization = %v, %v", ok, err)
+	}
+	if ok, err := c.DeactiveOrganization("3"); err != nil || !ok {
+		t.Errorf("DeactiveOrganization = %v, %v", ok, err)
+	}
+}
+
+func TestOrganizationContacts(t *testing.T) {
+	api, c := newFakeAPI(t)
+	contact := &Contact{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", JobTitle: "CTO", Telephone: "+44 20 7946 0000", TelephoneExtension: "12"}
+	api.reply("PUT /organization/3/contact", 204, nil)
+	api.reply("POST /organization/3/contact", 201, map[string]int{"id": 42})
+	api.reply("DELETE /organization/3/contact/42", 204, nil)
+
+	if ok, err := c.UpdateOrganizationContact("3", contact); err != nil || !ok {
+		t.Fatalf("UpdateOrganizationContact = %v, %v", ok, err)
+	}
+	var sent Contact
+	api.body("PUT /organization/3/contact", &sent)
+	if sent != *contact {
+		t.Errorf("The contact sent is %+v", sent)
+	}
+	res, err := c.AddOrganizationContact("3", contact)
+	if err != nil || res.ID != 42 {
+		t.Fatalf("AddOrganizationContact = %+v, %v", res, err)
+	}
+	if ok, err := c.RemoveOrganizationContact("3", "42"); err != nil || !ok {
+		t.Fatalf("RemoveOrganizationContact = %v, %v", ok, err)
+	}
+
+	bad := *contact
+	bad.TelephoneExtension = "x12"
+	if _, err := c.AddOrganizationContact("3", &bad); err == nil {
+		t.Error("A contact with an invalid extension is sent")
+	}
+}
+
+func TestOrganizationEVApprovers(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("POST /organization/3/ev-approver", 204, nil)
+	api.reply("DELETE /organization/3/ev-approver/8", 204, nil)
+	if ok, err := c.AddEVApprovers("3", &EVApproversRequest{EvApprovers: []IDReference{{ID: 8}, {ID: 9}}}); err != nil || !ok {
+		t.Fatalf("AddEVApprovers = %v, %v", ok, err)
+	}
+	var body map[string]interface{}
+	api.body("POST /organization/3/ev-approver", &body)
+	want := map[string]interface{}{"ev_approvers": []interface{}{map[string]interface{}{"id": 8.0}, map[string]interface{}{"id": 9.0}}}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+	if ok, err := c.RemoveEVApprover("3", "8"); err != nil || !ok {
+		t.Fatalf("RemoveEVApprover = %v, %v", ok, err)
+	}
+}
+
+func TestViewOrganizationAddressAndContact(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /organization/3", 200, map[string]interface{}{
+		"id": 3, "name": "Example Ltd", "address": "1 Main St", "address2": "Floor 2", "city": "London", "zip": "SW1A 1AA", "country": "gb",
+		"organization_contact": map[string]interface{}{"id": 42, "first_name": "Ada", "email": "ada@example.com", "telephone": "+44 20 7946 0000"},
+	})
+	org, err := c.ViewOrganization("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Address{Line1: "1 Main St", Line2: "Floor 2", City: "London", Zip: "SW1A 1AA", Country: "gb"}
+	if org.Address != want {
+		t.Errorf("Address = %+v, want %+v", org.Address, want)
+	}
+	if contact := org.OrganizationContact; contact.ID != 42 || contact.FirstName != "Ada" || contact.Telephone != "+44 20 7946 0000" {
+		t.Errorf("OrganizationContact = %+v", contact)
+	}
+}
+
+func TestShipInfoJSON(t *testing.T) {
+	ship := ShipInfo{Name: "Ada Lovelace", Address: Address{Line1: "1 Main St", City: "London", Zip: "SW1A 1AA", Country: "gb"}, Method: "STANDARD"}
+	data, err := json.Marshal(ship)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]string
+	json.Unmarshal(data, &fields)
+	if fields["addr1"] != "1 Main St" || fields["zip"] != "SW1A 1AA" || fields["method"] != "STANDARD" {
+		t.Errorf("ship_info = %s", data)
+	}
+	var decoded ShipInfo
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != ship {
+		t.Errorf("decoded = %+v, %v", decoded, err)
+	}
+	if err := (&ShipInfo{Address: Address{Country: "GBR"}}).Validate(); err == nil {
+		t.Error("An invalid country is accepted")
+	}
+}
+
+func TestSubmitOrganizationValidation(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("POST /organization/3/validation", 204, nil)
+	api.reply("POST /organization/4/validation", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_validation_type", "message": "Invalid validation type."}}})
+
+	res, err := c.SubmitOrganizationValidation("3", new(ValidateOrganizationRequest))
+	if err != nil || !res.Submitted || res.StatusCode != 204 {
+		t.Fatalf("SubmitOrganizationValidation = %+v, %v", res, err)
+	}
+	res, err = c.SubmitOrganizationValidation("4", new(ValidateOrganizationRequest))
+	if err == nil || res == nil || res.Submitted || res.StatusCode != 400 {
+		t.Fatalf("SubmitOrganizationValidation = %+v, %v, want the rejection", res, err)
+	}
+	if !c.ValidateOrganization("3", new(ValidateOrganizationRequest)) || c.ValidateOrganization("4", new(ValidateOrganizationRequest)) {
+		t.Error("ValidateOrganization does not report the submission")
+	}
+}
+
+func TestOrganizationCallErrors(t *testing.T) {
+	api, c := newFakeAPI(t)
+	apiError := map[string]interface{}{"errors": []map[string]string{{"code": "invalid_organization", "message": "Invalid organization."}}}
+	contact := &Contact{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Telephone: "+44 20 7946 0000"}
+	calls := []struct {
+		route string
+		call  func() (bool, error)
+	}{
+		{"PUT /organization/3", func() (bool, error) {
+			return c.UpdateOrganization("3", &UpdateOrganizationRequest{Name: "Example Ltd"})
+		}},
+		{"PUT /organization/3/activate", func() (bool, error) { return c.ActiveOrganization("3") }},
+		{"PUT /organization/3/deactivate", func() (bool, error) { return c.DeactiveOrganization("3") }},
+		{"PUT /organization/3/contact", func() (bool, error) { return c.UpdateOrganizationContact("3", contact) }},
+		{"DELETE /organization/3/contact/42", func() (bool, error) { return c.RemoveOrganizationContact("3", "42") }},
+		{"POST /organization/3/ev-approver", func() (bool, error) {
+			return c.AddEVApprovers("3", &EVApproversRequest{EvApprovers: []IDReference{{ID: 8}}})
+		}},
+		{"DELETE /organization/3/ev-approver/8", func() (bool, error) { return c.RemoveEVApprover("3", "8") }},
+	}
+	for _, tt := range calls {
+		api.reply(tt.route, 400, apiError)
+		if ok, err := tt.call(); ok || err == nil || err.Error() != "invalid_organization: Invalid organization." {
+			t.Errorf("%s = %v, %v, want the API error", tt.route, ok, err)
+		}
+		// An unexpected status without an error body still fails.
+		api.reply(tt.route, 200, map[string]interface{}{})
+		if ok, err := tt.call(); ok || err == nil {
+			t.Errorf("%s = %v, %v, want an unexpected status error", tt.route, ok, err)
+		}
+	}
+}
+
+func TestNewOrganizationContainer(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("POST /organization", 201, map[string]int{"id": 3})
+	request := &NewOrganizationRequest{
+		Name:                "Example Ltd",
+		Address:             Address{Line1: "1 Main St", City: "London", Zip: "SW1A 1AA", Country: "gb"},
+		Telephone:           "+44 20 7946 0000",
+		OrganizationContact: Contact{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Telephone: "+44 20 7946 0000"},
+		Container:           &IDReference{ID: 7},
+	}
+	if _, err := c.NewOrganization(request); err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	api.body("POST /organization", &body)
+	if !reflect.DeepEqual(body["container"], map[string]interface{}{"id": 7.0}) {
+		t.Errorf("container = %v", body["container"])
+	}
+}
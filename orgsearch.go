@@ -0,0 +1,251 @@
+package digicert
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// OrganizationFilter presents the criteria of SearchOrganizations, zero fields match everything.
+type OrganizationFilter struct {
+	// Name and AssumedName match case-insensitively on a part of the normalized name.
+	Name        string
+	AssumedName string
+	Country     string
+	ContainerID int
+	// Status is the organization status, e.g. active or inactive.
+	Status string
+}
+
+// OrganizationDuplicateGroup presents organizations that are likely the same company, with what references each of them.
+type OrganizationDuplicateGroup struct {
+	// Key is the normalized name of the first organization and the normalized address they share.
+	Key           string
+	Organizations []ListedOrganization
+	// OrderIDs and DomainIDs are keyed by organization ID.
+	OrderIDs  map[int][]int
+	DomainIDs map[int][]int
+}
+
+// SearchOrganizations exports Use this to find organizations by name, assumed name, country, container or status.
+func (c *Client) SearchOrganizations(filter *OrganizationFilter) ([]ListedOrganization, error) {
+	all, err := c.ListAllOrganizations()
+	if err != nil {
+		return nil, err
+	}
+	if err := all.err(); err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return all.Organizations, nil
+	}
+	var found []ListedOrganization
+	for _, org := range all.Organizations {
+		switch {
+		case filter.Name != "" && !strings.Contains(normalizeOrganizationName(org.Name+" "+org.DisplayName), normalizeOrganizationName(filter.Name)):
+		case filter.AssumedName != "" && !strings.Contains(normalizeOrganizationName(org.AssumedName), normalizeOrganizationName(filter.AssumedName)):
+		case filter.Country != "" && !strings.EqualFold(org.Country, filter.Country):
+		case filter.ContainerID != 0 && org.Container.ID != filter.ContainerID:
+		case filter.Status != "" && !strings.EqualFold(org.Status, filter.Status):
+		default:
+			found = append(found, org)
+		}
+	}
+	return found, nil
+}
+
+// FindDuplicateOrganizations exports Use this to group the organizations sharing a normalized address whose names are alike, ignoring case, punctuation, legal suffixes such as Inc or Ltd and street abbreviations such as St or Ave.
+// Names are alike when they have the same words in any order or differ by a typo, see similarOrganizationNames.
+// Each group lists the orders and domains referencing every organization so they can be consolidated.
+func (c *Client) FindDuplicateOrganizations() ([]OrganizationDuplicateGroup, error) {
+	all, err := c.ListAllOrganizations()
+	if err != nil {
+		return nil, err
+	}
+	if err := all.err(); err != nil {
+		return nil, err
+	}
+	var groups []*OrganizationDuplicateGroup
+	names := make(map[*OrganizationDuplicateGroup][]string)
+	byAddress := make(map[string][]*OrganizationDuplicateGroup)
+next:
+	for _, org := range all.Organizations {
+		name, address := normalizeOrganizationName(org.Name), normalizeAddress(org.Address)
+		for _, g := range byAddress[address] {
+			for _, other := range names[g] {
+				if similarOrganizationNames(name, other) {
+					g.Organizations = append(g.Organizations, org)
+					names[g] = append(names[g], name)
+					continue next
+				}
+			}
+		}
+		g := &OrganizationDuplicateGroup{Key: name + "|" + address, Organizations: []ListedOrganization{org}, OrderIDs: make(map[int][]int), DomainIDs: make(map[int][]int)}
+		groups = append(groups, g)
+		names[g] = []string{name}
+		byAddress[address] = append(byAddress[address], g)
+	}
+	duplicated := make(map[int]*OrganizationDuplicateGroup)
+	containers := make(map[int]bool)
+	var result []OrganizationDuplicateGroup
+	for _, g := range groups {
+		if len(g.Organizations) < 2 {
+			continue
+		}
+		for _, org := range g.Organizations {
+			duplicated[org.ID] = g
+			containers[org.Container.ID] = true
+		}
+	}
+	if len(duplicated) == 0 {
+		return nil, nil
+	}
+
+	err = c.eachOrderPage(func(page *ListOrders) bool {
+		for _, o := range page.Orders {
+			if g, ok := duplicated[o.Organization.ID]; ok {
+				g.OrderIDs[o.Organization.ID] = append(g.OrderIDs[o.Organization.ID], o.ID)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	for containerID := range containers {
+		list, err := c.ListDomains(strconv.Itoa(containerID))
+		if err != nil {
+			return nil, err
+		}
+		if err := list.err(); err != nil {
+			return nil, err
+		}
+		for _, d := range list.Domains {
+			if g, ok := duplicated[d.Organization.ID]; ok && !seen[d.ID] {
+				seen[d.ID] = true
+				g.DomainIDs[d.Organization.ID] = append(g.DomainIDs[d.Organization.ID], d.ID)
+			}
+		}
+	}
+
+	for _, g := range groups {
+		if len(g.Organizations) > 1 {
+			result = append(result, *g)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// legalSuffixes are dropped when comparing organization names.
+var legalSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "llp": true, "ltd": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true, "plc": true,
+	"gmbh": true, "ag": true, "sa": true, "sarl": true, "bv": true, "nv": true, "pty": true, "kk": true,
+}
+
+// normalizeOrganizationName lowercases name, drops punctuation and legal suffixes and collapses spaces.
+func normalizeOrganizationName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	for len(words) > 1 && legalSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// similarOrganizationNames reports whether the normalized names a and b are likely the same company: the same words in any order, or a few edits apart.
+// Names shorter than 5 letters must be equal, names up to 11 letters may differ by one edit and longer ones by two, e.g. Exampel and Example.
+func similarOrganizationNames(a, b string) bool {
+	if a == b {
+		return true
+	}
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	sort.Strings(wa)
+	sort.Strings(wb)
+	if strings.Join(wa, " ") == strings.Join(wb, " ") {
+		return true
+	}
+	ra, rb := []rune(a), []rune(b)
+	n := len(ra)
+	if len(rb) < n {
+		n = len(rb)
+	}
+	var tolerance int
+	switch {
+	case n < 5:
+		return false
+	case n < 12:
+		tolerance = 1
+	default:
+		tolerance = 2
+	}
+	return editDistance(ra, rb) <= tolerance
+}
+
+// editDistance returns the number of inserted, deleted, replaced or swapped adjacent runes turning a into b.
+func editDistance(a, b []rune) int {
+	// prev2, prev and cur are the rows of the distances from the prefixes of a to the prefixes of b.
+	prev2, prev, cur := make([]int, len(b)+1), make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := prev[j-1] + cost
+			if prev[j]+1 < d {
+				d = prev[j] + 1
+			}
+			if cur[j-1]+1 < d {
+				d = cur[j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && prev2[j-2]+1 < d {
+				d = prev2[j-2] + 1
+			}
+			cur[j] = d
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// addressAbbreviations maps the usual address words to the abbreviation they are compared as.
+var addressAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "road": "rd", "boulevard": "blvd", "drive": "dr", "lane": "ln",
+	"court": "ct", "place": "pl", "square": "sq", "highway": "hwy", "parkway": "pkwy",
+	"suite": "ste", "floor": "fl", "building": "bldg", "north": "n", "south": "s", "east": "e", "west": "w",
+}
+
+// normalizeAddress lowercases the address, drops punctuation, abbreviates the usual words and the spaces of the zip code.
+func normalizeAddress(a Address) string {
+	fields := []string{a.Line1 + " " + a.Line2, a.City, a.State}
+	for i, field := range fields {
+		words := strings.FieldsFunc(strings.ToLower(field), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for j, w := range words {
+			if abbr, ok := addressAbbreviations[w]; ok {
+				words[j] = abbr
+			}
+		}
+		fields[i] = strings.Join(words, " ")
+	}
+	zip := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, a.Zip)
+	return strings.Join(append(fields, zip, strings.ToLower(a.Country)), "|")
+}
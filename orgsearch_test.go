@@ -0,0 +1,117 @@
+package digicert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchOrganizations(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /organization", 200, map[string]interface{}{"organizations": []map[string]interface{}{
+		{"id": 1, "name": "Example Ltd", "assumed_name": "Example Shop", "country": "gb", "status": "active", "container": map[string]int{"id": 1}},
+		{"id": 2, "name": "Example, Inc.", "display_name": "Example Americas", "country": "us", "status": "inactive", "container": map[string]int{"id": 2}},
+		{"id": 3, "name": "Other GmbH", "country": "de", "status": "active", "container": map[string]int{"id": 2}},
+	}})
+	tests := []struct {
+		name   string
+		filter *OrganizationFilter
+		want   []int
+	}{
+		{"nil", nil, []int{1, 2, 3}},
+		{"zero", &OrganizationFilter{}, []int{1, 2, 3}},
+		{"name", &OrganizationFilter{Name: "EXAMPLE inc"}, []int{1, 2}},
+		{"display name", &OrganizationFilter{Name: "americas"}, []int{2}},
+		{"assumed name", &OrganizationFilter{AssumedName: "shop"}, []int{1}},
+		{"country", &OrganizationFilter{Country: "GB"}, []int{1}},
+		{"container", &OrganizationFilter{ContainerID: 2}, []int{2, 3}},
+		{"status", &OrganizationFilter{Status: "Active"}, []int{1, 3}},
+		{"all fields", &OrganizationFilter{Name: "example", Country: "us", ContainerID: 2, Status: "inactive"}, []int{2}},
+		{"no match", &OrganizationFilter{Name: "example", Country: "de"}, nil},
+	}
+	for _, tt := range tests {
+		orgs, err := c.SearchOrganizations(tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int
+		for _, o := range orgs {
+			ids = append(ids, o.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%s: SearchOrganizations = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
+
+func TestSimilarOrganizationNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"example", "example", true},
+		{"example", "exampel", true},
+		{"example", "examples", true},
+		{"example", "sample", false},
+		{"smith & sons", "sons & smith", true},
+		{"acme", "acne", false},
+		{"northwind traders", "northwind tradrs", true},
+		{"northwind traders", "northwind trading", false},
+		{"example holdings", "exmaple holdngs", true},
+		{"example", "other", false},
+	}
+	for _, tt := range tests {
+		if got := similarOrganizationNames(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarOrganizationNames(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicateOrganizations(t *testing.T) {
+	api, c := newFakeAPI(t)
+	org := func(id int, name, line1, zip string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": name, "address": line1, "city": "London", "zip": zip, "country": "gb", "container": map[string]int{"id": 1}}
+	}
+	api.reply("GET /organization", 200, map[string]interface{}{"organizations": []interface{}{
+		org(1, "Example Ltd", "1 Main Street", "SW1A 1AA"),
+		org(2, "EXAMPLE, Limited", "1 main st.", "sw1a1aa"),
+		// A typo in the name is still the same company.
+		org(4, "Exmaple Ltd.", "1 Main St", "SW1A 1AA"),
+		// Another company at the same address is not.
+		org(5, "Northwind Ltd", "1 Main Street", "SW1A 1AA"),
+		// The same name at another address is another branch.
+		org(3, "Example Ltd", "200 High Street", "EC1A 1BB"),
+	}})
+	api.reply("GET /order/certificate/", 200, map[string]interface{}{
+		"orders": []interface{}{map[string]interface{}{"id": 10, "organization": map[string]int{"id": 2}}},
+		"page":   map[string]int{"total": 1},
+	})
+	api.reply("GET /domain", 200, map[string]interface{}{"domains": []interface{}{
+		map[string]interface{}{"id": 20, "name": "example.co.uk", "organization": map[string]int{"id": 1}},
+	}})
+
+	groups, err := c.FindDuplicateOrganizations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("groups = %+v, want organizations 1, 2 and 4", groups)
+	}
+	var ids []int
+	for _, o := range groups[0].Organizations {
+		ids = append(ids, o.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2, 4}) {
+		t.Errorf("The duplicates are %v, want [1 2 4]", ids)
+	}
+	if ids := groups[0].OrderIDs[2]; len(ids) != 1 || ids[0] != 10 {
+		t.Errorf("OrderIDs = %v", groups[0].OrderIDs)
+	}
+	if ids := groups[0].DomainIDs[1]; len(ids) != 1 || ids[0] != 20 {
+		t.Errorf("DomainIDs = %v", groups[0].DomainIDs)
+	}
+
+	api.reply("GET /domain", 200, map[string]interface{}{"errors": []map[string]string{{"code": "access_denied", "message": "Permission denied."}}})
+	if _, err := c.FindDuplicateOrganizations(); err == nil {
+		t.Error("The domain listing error is ignored")
+	}
+}
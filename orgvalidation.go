@@ -0,0 +1,108 @@
+package digicert
+
+import (
+	"strconv"
+	"time"
+)
+
+// OrganizationValidationTypes are the validation types tracked by TrackOrganizationValidations.
+var OrganizationValidationTypes = []string{"ov", "ev", "cs", "ev_cs"}
+
+// ValidationState presents where an organization stands for a validation type.
+type ValidationState string
+
+// Validation states reported by TrackOrganizationValidations.
+const (
+	ValidationComplete ValidationState = "complete"
+	ValidationExpiring ValidationState = "expiring"
+	ValidationPending  ValidationState = "pending"
+	ValidationExpired  ValidationState = "expired"
+	ValidationMissing  ValidationState = "missing"
+)
+
+// OrganizationValidationStatus presents the state of one validation type of an organization.
+type OrganizationValidationStatus struct {
+	Type           string
+	State          ValidationState
+	ValidatedUntil time.Time
+}
+
+// OrganizationValidationSummary presents the validation states of an organization, keyed by validation type.
+type OrganizationValidationSummary struct {
+	ID          int
+	Name        string
+	Validations map[string]OrganizationValidationStatus
+	Err         error
+}
+
+// Ready reports whether an order needing validationType can be issued without waiting for a (re)validation.
+func (s *OrganizationValidationSummary) Ready(validationType string) bool {
+	state := s.Validations[validationType].State
+	return state == ValidationComplete || state == ValidationExpiring
+}
+
+// OrganizationValidationOptions presents the options of TrackOrganizationValidations.
+type OrganizationValidationOptions struct {
+	RateLimit
+}
+
+// TrackOrganizationValidations exports Use this to know, before ordering, which validation types (ov, ev, cs, ev_cs) each organization has complete, pending, expiring within the given window, expired or missing.
+// Organizations whose validations cannot be read are reported with Err set.
+func (c *Client) TrackOrganizationValidations(within time.Duration, options *OrganizationValidationOptions) ([]OrganizationValidationSummary, error) {
+	if options == nil {
+		options = new(OrganizationValidationOptions)
+	}
+	concurrency, interval := options.limits()
+	orgs, err := c.clone().ListAllOrganizations()
+	if err != nil {
+		return nil, err
+	}
+	if err := orgs.err(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	summaries := make([]OrganizationValidationSummary, len(orgs.Organizations))
+	throttle(len(summaries), concurrency, interval, func(i int) {
+		org := orgs.Organizations[i]
+		s := OrganizationValidationSummary{
+			ID:          org.ID,
+			Name:        org.Name,
+			Validations: make(map[string]OrganizationValidationStatus),
+		}
+		for _, t := range OrganizationValidationTypes {
+			s.Validations[t] = OrganizationValidationStatus{Type: t, State: ValidationMissing}
+		}
+		res, err := c.clone().ViewOrganizationValidation(strconv.Itoa(org.ID))
+		if err == nil {
+			err = res.err()
+		}
+		if err != nil {
+			s.Err = err
+		} else {
+			for _, v := range res.Validations {
+				s.Validations[v.Type] = OrganizationValidationStatus{
+					Type:           v.Type,
+					State:          validationState(v, now, within),
+					ValidatedUntil: v.ValidatedUntil.Time,
+				}
+			}
+		}
+		summaries[i] = s
+	})
+	return summaries, nil
+}
+
+// validationState classifies a validation from its status and end date.
+func validationState(v OrganizationValidation, now time.Time, within time.Duration) ValidationState {
+	switch {
+	case v.Status == "pending":
+		return ValidationPending
+	case !v.ValidatedUntil.IsZero() && !v.ValidatedUntil.After(now):
+		return ValidationExpired
+	case v.Status != "active" && v.Status != "validated" && v.Status != "completed":
+		return ValidationPending
+	case !v.ValidatedUntil.IsZero() && v.ValidatedUntil.Before(now.Add(within)):
+		return ValidationExpiring
+	}
+	return ValidationComplete
+}
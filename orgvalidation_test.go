@@ -0,0 +1,75 @@
+package digicert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackOrganizationValidations(t *testing.T) {
+	api, c := newFakeAPI(t)
+	day := func(d int) string { return time.Now().AddDate(0, 0, d).UTC().Format("2006-01-02") }
+	api.reply("GET /organization", 200, map[string]interface{}{"organizations": []map[string]interface{}{
+		{"id": 1, "name": "Example Ltd"},
+		{"id": 2, "name": "Example Inc"},
+		{"id": 3, "name": "Hidden Ltd"},
+	}})
+	api.reply("GET /organization/1/validation", 200, map[string]interface{}{"validations": []map[string]string{
+		{"type": "ov", "status": "active", "validated_until": day(300)},
+		{"type": "ev", "status": "active", "validated_until": day(10)},
+		{"type": "cs", "status": "pending"},
+	}})
+	api.reply("GET /organization/2/validation", 200, map[string]interface{}{"validations": []map[string]string{
+		{"type": "ov", "status": "active", "validated_until": day(-1)},
+	}})
+	api.reply("GET /organization/3/validation", 200, map[string]interface{}{"errors": []map[string]string{{"code": "access_denied", "message": "Access denied."}}})
+
+	summaries, err := c.TrackOrganizationValidations(30*24*time.Hour, &OrganizationValidationOptions{RateLimit{Interval: -1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 3 {
+		t.Fatalf("summaries = %+v", summaries)
+	}
+	want := map[string]ValidationState{"ov": ValidationComplete, "ev": ValidationExpiring, "cs": ValidationPending, "ev_cs": ValidationMissing}
+	for typ, state := range want {
+		if got := summaries[0].Validations[typ].State; got != state {
+			t.Errorf("organization 1 %s = %s, want %s", typ, got, state)
+		}
+	}
+	if !summaries[0].Ready("ov") || !summaries[0].Ready("ev") || summaries[0].Ready("cs") || summaries[0].Ready("ev_cs") {
+		t.Errorf("organization 1 readiness is wrong: %+v", summaries[0].Validations)
+	}
+	if got := summaries[1].Validations["ov"].State; got != ValidationExpired || summaries[1].Ready("ov") {
+		t.Errorf("organization 2 ov = %s, want expired", got)
+	}
+	if summaries[2].Err == nil || summaries[2].Validations["ov"].State != ValidationMissing {
+		t.Errorf("organization 3 = %+v, want its error", summaries[2])
+	}
+}
+
+func TestValidationState(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	date := func(d int) Date { return Date{now.AddDate(0, 0, d)} }
+	tests := []struct {
+		status string
+		until  Date
+		want   ValidationState
+	}{
+		{"active", date(100), ValidationComplete},
+		{"validated", Date{}, ValidationComplete},
+		{"completed", date(100), ValidationComplete},
+		{"active", date(10), ValidationExpiring},
+		{"active", date(0), ValidationExpired},
+		{"active", date(-10), ValidationExpired},
+		{"pending", date(-10), ValidationPending},
+		{"pending", date(100), ValidationPending},
+		{"waiting", Date{}, ValidationPending},
+		{"", Date{}, ValidationPending},
+	}
+	for _, tt := range tests {
+		v := OrganizationValidation{Status: tt.status, ValidatedUntil: tt.until}
+		if got := validationState(v, now, 30*24*time.Hour); got != tt.want {
+			t.Errorf("validationState(%q, %v) = %s, want %s", tt.status, tt.until.Time, got, tt.want)
+		}
+	}
+}
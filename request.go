@@ -3,59 +3,69 @@ package digicert
 import (
 	"encoding/json"
 	"errors"
+	"net/url"
+	"strconv"
 	"time"
 )
 
+// ListedRequest presents a request of ListRequests
+type ListedRequest struct {
+	ID        int           `json:"id"`
+	Date      DateTime      `json:"date"`
+	Type      string        `json:"type"`
+	Status    RequestStatus `json:"status"`
+	Requester struct {
+		ID        int    `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
+	} `json:"requester,omitempty"`
+	Processor struct {
+		ID        int    `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
+	} `json:"processor,omitempty"`
+	Order struct {
+		ID          int `json:"id"`
+		Certificate struct {
+			CommonName string `json:"common_name"`
+		} `json:"certificate"`
+		Organization struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"organization"`
+		Container struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"container"`
+		Product struct {
+			NameID ProductNameID `json:"name_id"`
+			Name   string        `json:"name"`
+			Type   string        `json:"type"`
+		} `json:"product"`
+	} `json:"order"`
+}
+
 // ListRequestsResponse presents a list of request
 type ListRequestsResponse struct {
-	Requests []struct {
-		ID        int       `json:"id"`
-		Date      time.Time `json:"date"`
-		Type      string    `json:"type"`
-		Status    string    `json:"status"`
-		Requester struct {
-			ID        int    `json:"id"`
-			FirstName string `json:"first_name"`
-			LastName  string `json:"last_name"`
-			Email     string `json:"email"`
-		} `json:"requester,omitempty"`
-		Processor struct {
-			ID        int    `json:"id"`
-			FirstName string `json:"first_name"`
-			LastName  string `json:"last_name"`
-			Email     string `json:"email"`
-		} `json:"processor,omitempty"`
-		Order struct {
-			ID          int `json:"id"`
-			Certificate struct {
-				CommonName string `json:"common_name"`
-			} `json:"certificate"`
-			Organization struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
-			} `json:"organization"`
-			Container struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
-			} `json:"container"`
-			Product struct {
-				NameID string `json:"name_id"`
-				Name   string `json:"name"`
-				Type   string `json:"type"`
-			} `json:"product"`
-		} `json:"order"`
-	} `json:"requests"`
+	Requests []ListedRequest `json:"requests"`
+	Page     struct {
+		Total  int `json:"total"`
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+	} `json:"page"`
 
 	SchemeValidationErrors
 }
 
 // ViewRequestResponse presents view a request detail.
 type ViewRequestResponse struct {
-	ID            int       `json:"id"`
-	Date          time.Time `json:"date"`
-	Type          string    `json:"type"`
-	Status        string    `json:"status"`
-	DateProcessed time.Time `json:"date_processed"`
+	ID            int           `json:"id"`
+	Date          DateTime      `json:"date"`
+	Type          string        `json:"type"`
+	Status        RequestStatus `json:"status"`
+	DateProcessed DateTime      `json:"date_processed"`
 	Requester     struct {
 		ID        int    `json:"id"`
 		FirstName string `json:"first_name"`
@@ -71,10 +81,10 @@ type ViewRequestResponse struct {
 	Order struct {
 		ID          int `json:"id"`
 		Certificate struct {
-			CommonName   string    `json:"common_name"`
-			DNSNames     []string  `json:"dns_names"`
-			DateCreated  time.Time `json:"date_created"`
-			Csr          string    `json:"csr"`
+			CommonName   string   `json:"common_name"`
+			DNSNames     []string `json:"dns_names"`
+			DateCreated  DateTime `json:"date_created"`
+			Csr          string   `json:"csr"`
 			Organization struct {
 				ID      int    `json:"id"`
 				Name    string `json:"name"`
@@ -88,16 +98,16 @@ type ViewRequestResponse struct {
 				InstallURL string `json:"install_url"`
 				CsrURL     string `json:"csr_url"`
 			} `json:"server_platform"`
-			SignatureHash string `json:"signature_hash"`
-			KeySize       int    `json:"key_size"`
+			SignatureHash SignatureHash `json:"signature_hash"`
+			KeySize       int           `json:"key_size"`
 			CaCert        struct {
 				ID   string `json:"id"`
 				Name string `json:"name"`
 			} `json:"ca_cert"`
 		} `json:"certificate"`
-		Status       string    `json:"status"`
-		IsRenewal    bool      `json:"is_renewal"`
-		DateCreated  time.Time `json:"date_created"`
+		Status       OrderStatus `json:"status"`
+		IsRenewal    bool        `json:"is_renewal"`
+		DateCreated  DateTime    `json:"date_created"`
 		Organization struct {
 			ID      int    `json:"id"`
 			Name    string `json:"name"`
@@ -113,12 +123,12 @@ type ViewRequestResponse struct {
 			Name string `json:"name"`
 		} `json:"container"`
 		Product struct {
-			NameID                string `json:"name_id"`
-			Name                  string `json:"name"`
-			Type                  string `json:"type"`
-			ValidationType        string `json:"validation_type"`
-			ValidationName        string `json:"validation_name"`
-			ValidationDescription string `json:"validation_description"`
+			NameID                ProductNameID `json:"name_id"`
+			Name                  string        `json:"name"`
+			Type                  string        `json:"type"`
+			ValidationType        string        `json:"validation_type"`
+			ValidationName        string        `json:"validation_name"`
+			ValidationDescription string        `json:"validation_description"`
 		} `json:"product"`
 		OrganizationContact struct {
 			FirstName string `json:"first_name"`
@@ -141,24 +151,15 @@ type ViewRequestResponse struct {
 			Email     string `json:"email"`
 		} `json:"user"`
 		Requests []struct {
-			ID       int       `json:"id"`
-			Date     time.Time `json:"date"`
-			Type     string    `json:"type"`
-			Status   string    `json:"status"`
-			Comments string    `json:"comments"`
+			ID       int           `json:"id"`
+			Date     DateTime      `json:"date"`
+			Type     string        `json:"type"`
+			Status   RequestStatus `json:"status"`
+			Comments string        `json:"comments"`
 		} `json:"requests"`
-		CsProvisioningMethod string `json:"cs_provisioning_method"`
-		ShipInfo             struct {
-			Name    string `json:"name"`
-			Addr1   string `json:"addr1"`
-			Addr2   string `json:"addr2"`
-			City    string `json:"city"`
-			State   string `json:"state"`
-			Zip     int    `json:"zip"`
-			Country string `json:"country"`
-			Method  string `json:"method"`
-		} `json:"ship_info"`
-		DisableCt bool `json:"disable_ct"`
+		CsProvisioningMethod string   `json:"cs_provisioning_method"`
+		ShipInfo             ShipInfo `json:"ship_info"`
+		DisableCt            bool     `json:"disable_ct"`
 	} `json:"order"`
 	Comments         string `json:"comments"`
 	ProcessorComment string `json:"processor_comment"`
@@ -168,27 +169,77 @@ type ViewRequestResponse struct {
 
 // UpdateRequestStatusRequest presents update "request" status
 type UpdateRequestStatusRequest struct {
-	Status           string `json:"status"`
-	ProcessorComment string `json:"processor_comment"`
+	Status           RequestStatus `json:"status"`
+	ProcessorComment string        `json:"processor_comment"`
+}
+
+// RequestFilter presents the filters of ListRequests, zero fields are not sent.
+type RequestFilter struct {
+	Status      RequestStatus
+	Type        string
+	ContainerID int
+	RequesterID int
+	// From and To bound the date of the requests.
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
 }
 
-// ListRequests exports Use this endpoint to retrieve a list of certificate requests.
-// Request statuses include: pending, approved, rejected and empty(returns all requests).
-func (c *Client) ListRequests(status string) (*ListRequestsResponse, error) {
-	switch status {
-	case "pending":
-		status = "pending"
-	case "approved":
-		status = "approved"
-	case "rejected":
-		status = "rejected"
-	case "":
-		status = ""
-	default:
+// Query returns the query string parameters of the filter.
+func (f *RequestFilter) Query() (url.Values, error) {
+	q := make(url.Values)
+	if f == nil {
+		return q, nil
+	}
+	if f.Status != "" && !f.Status.Known() {
 		return nil, errors.New("The status are not accepted")
 	}
+	if f.Limit < 0 || f.Offset < 0 {
+		return nil, errors.New("The limit and offset must not be negative")
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return nil, errors.New("The date range ends before it starts")
+	}
+	if f.Status != "" {
+		q.Set("filters[status]", f.Status.String())
+	}
+	if f.Type != "" {
+		q.Set("filters[type]", f.Type)
+	}
+	if f.ContainerID != 0 {
+		q.Set("container_id", strconv.Itoa(f.ContainerID))
+	}
+	if f.RequesterID != 0 {
+		q.Set("filters[requester_id]", strconv.Itoa(f.RequesterID))
+	}
+	if !f.From.IsZero() {
+		q.Set("filters[date_from]", f.From.UTC().Format("2006-01-02T15:04:05"))
+	}
+	if !f.To.IsZero() {
+		q.Set("filters[date_to]", f.To.UTC().Format("2006-01-02T15:04:05"))
+	}
+	if f.Limit != 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.Offset != 0 {
+		q.Set("offset", strconv.Itoa(f.Offset))
+	}
+	return q, nil
+}
+
+// ListRequests exports Use this endpoint to retrieve a list of certificate requests matching filter, a nil filter returns all requests.
+func (c *Client) ListRequests(filter *RequestFilter) (*ListRequestsResponse, error) {
+	q, err := filter.Query()
+	if err != nil {
+		return nil, err
+	}
+	uri := "/request"
+	if len(q) > 0 {
+		uri += "?" + q.Encode()
+	}
 	c.result = new(ListRequestsResponse)
-	data, err := c.makeRequest("GET", "/request?"+status, nil)
+	data, err := c.makeRequest("GET", uri, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +249,73 @@ func (c *Client) ListRequests(status string) (*ListRequestsResponse, error) {
 	return c.result.(*ListRequestsResponse), err
 }
 
+// RequestIterator exports walks all the requests of a filter, fetching the pages as needed:
+//
+//	it := c.IterateRequests(&digicert.RequestFilter{Status: digicert.RequestPending})
+//	for it.Next() {
+//		r := it.Request()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type RequestIterator struct {
+	client  *Client
+	filter  RequestFilter
+	page    []ListedRequest
+	current int
+	done    bool
+	err     error
+}
+
+// IterateRequests exports returns an iterator over every request matching filter, Limit is the page size and defaults to 100.
+func (c *Client) IterateRequests(filter *RequestFilter) *RequestIterator {
+	it := &RequestIterator{client: c.clone(), current: -1}
+	if filter != nil {
+		it.filter = *filter
+	}
+	if it.filter.Limit == 0 {
+		it.filter.Limit = 100
+	}
+	return it
+}
+
+// Next advances to the next request, it returns false once the requests are exhausted or a page failed.
+func (it *RequestIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.current++
+	if it.current < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+	res, err := it.client.ListRequests(&it.filter)
+	if err == nil {
+		err = res.err()
+	}
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page, it.current = res.Requests, 0
+	it.filter.Offset += len(res.Requests)
+	if len(res.Requests) < it.filter.Limit || (res.Page.Total > 0 && it.filter.Offset >= res.Page.Total) {
+		it.done = true
+	}
+	return len(it.page) > 0
+}
+
+// Request returns the current request.
+func (it *RequestIterator) Request() *ListedRequest {
+	return &it.page[it.current]
+}
+
+// Err returns the error which stopped the iteration.
+func (it *RequestIterator) Err() error {
+	return it.err
+}
+
 // ViewRequest exports Use this endpoint to retrieve a certificate request.
 func (c *Client) ViewRequest(requestID string) (*ViewRequestResponse, error) {
 	c.result = new(ViewRequestResponse)
@@ -211,10 +329,17 @@ func (c *Client) ViewRequest(requestID string) (*ViewRequestResponse, error) {
 	return c.result.(*ViewRequestResponse), err
 }
 
-// UpdateRequestStatus exports Use this endpoint to retrieve the status of a previously submitted certificate request.
+// UpdateRequestStatus exports Use this endpoint to update the status of a previously submitted certificate request.
 // Statuses [REQUIRED]: submitted, pending, approved, rejected
-func (c *Client) UpdateRequestStatus(requestID string) (bool, error) {
-	_, err := c.makeRequest("PUT", "/request/"+requestID+"/status", nil)
+func (c *Client) UpdateRequestStatus(requestID string, request *UpdateRequestStatusRequest) (bool, error) {
+	if request == nil {
+		return false, errors.New("The request status must input")
+	}
+	if !request.Status.Known() {
+		return false, errors.New("The status are not accepted")
+	}
+	c.request = request
+	data, err := c.makeRequest("PUT", "/request/"+requestID+"/status", nil)
 	if err != nil {
 		return false, err
 	}
@@ -222,5 +347,21 @@ func (c *Client) UpdateRequestStatus(requestID string) (bool, error) {
 	if c.statusCode == 204 {
 		return true, err
 	}
-	return false, err
+	var res SchemeValidationErrors
+	if err := json.Unmarshal(data, &res); err == nil {
+		if err := res.err(); err != nil {
+			return false, err
+		}
+	}
+	return false, errors.New("The request status was not updated, status " + strconv.Itoa(c.statusCode))
+}
+
+// ApproveRequest exports approves a pending certificate request with the processor comment.
+func (c *Client) ApproveRequest(requestID, comment string) (bool, error) {
+	return c.UpdateRequestStatus(requestID, &UpdateRequestStatusRequest{Status: RequestApproved, ProcessorComment: comment})
+}
+
+// RejectRequest exports rejects a pending certificate request, the processor comment tells the requester why.
+func (c *Client) RejectRequest(requestID, comment string) (bool, error) {
+	return c.UpdateRequestStatus(requestID, &UpdateRequestStatusRequest{Status: RequestRejected, ProcessorComment: comment})
 }
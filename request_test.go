@@ -0,0 +1,112 @@
+package digicert
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRequestFilterQuery(t *testing.T) {
+	from := time.Date(2024, 3, 1, 8, 30, 0, 0, time.FixedZone("CET", 3600))
+	to := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	for _, test := range []struct {
+		filter *RequestFilter
+		query  string
+	}{
+		{nil, ""},
+		{&RequestFilter{}, ""},
+		{&RequestFilter{Status: RequestPending}, "filters%5Bstatus%5D=pending"},
+		{&RequestFilter{Type: "new_request"}, "filters%5Btype%5D=new_request"},
+		{&RequestFilter{ContainerID: 12}, "container_id=12"},
+		{&RequestFilter{RequesterID: 34}, "filters%5Brequester_id%5D=34"},
+		{&RequestFilter{From: from}, "filters%5Bdate_from%5D=2024-03-01T07%3A30%3A00"},
+		{&RequestFilter{To: to}, "filters%5Bdate_to%5D=2024-03-31T23%3A59%3A59"},
+		{&RequestFilter{Limit: 50}, "limit=50"},
+		{&RequestFilter{Offset: 100}, "offset=100"},
+		{
+			&RequestFilter{Status: RequestApproved, Type: "revoke", ContainerID: 1, RequesterID: 2, From: from, To: to, Limit: 10, Offset: 20},
+			"container_id=1&filters%5Bdate_from%5D=2024-03-01T07%3A30%3A00&filters%5Bdate_to%5D=2024-03-31T23%3A59%3A59&filters%5Brequester_id%5D=2&filters%5Bstatus%5D=approved&filters%5Btype%5D=revoke&limit=10&offset=20",
+		},
+	} {
+		q, err := test.filter.Query()
+		if err != nil {
+			t.Errorf("%+v: %v", test.filter, err)
+			continue
+		}
+		if got := q.Encode(); got != test.query {
+			t.Errorf("%+v: query = %s, want %s", test.filter, got, test.query)
+		}
+	}
+
+	for _, filter := range []*RequestFilter{
+		{Status: "processing"},
+		{Limit: -1},
+		{Offset: -1},
+		{From: to, To: from},
+	} {
+		if _, err := filter.Query(); err == nil {
+			t.Errorf("%+v is accepted", filter)
+		}
+	}
+}
+
+func TestRequestIteratorPages(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		total int
+		// pageTotal is the total reported by the API, 0 when it is not reported.
+		pageTotal int
+		calls     []string
+	}{
+		// The last page is short.
+		{"short last page", 5, 0, []string{"GET /request?limit=2", "GET /request?limit=2&offset=2", "GET /request?limit=2&offset=4"}},
+		// The last page is full, the total tells it is the last.
+		{"total reached", 4, 4, []string{"GET /request?limit=2", "GET /request?limit=2&offset=2"}},
+		// Without a total, a full last page needs an empty page to stop.
+		{"empty page", 4, 0, []string{"GET /request?limit=2", "GET /request?limit=2&offset=2", "GET /request?limit=2&offset=4"}},
+		{"no requests", 0, 0, []string{"GET /request?limit=2"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			api, c := newFakeAPI(t)
+			api.handle("GET /request", func(w http.ResponseWriter, r *http.Request) {
+				offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+				var page []map[string]int
+				for id := offset; id < test.total && id < offset+2; id++ {
+					page = append(page, map[string]int{"id": id})
+				}
+				writeJSON(w, 200, map[string]interface{}{"requests": page, "page": map[string]int{"total": test.pageTotal}})
+			})
+			it := c.IterateRequests(&RequestFilter{Limit: 2})
+			var ids []int
+			for it.Next() {
+				ids = append(ids, it.Request().ID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if len(ids) != test.total {
+				t.Errorf("ids = %v, want %d requests", ids, test.total)
+			}
+			if got := api.requests(); !reflect.DeepEqual(got, test.calls) {
+				t.Errorf("calls = %v, want %v", got, test.calls)
+			}
+			if it.Next() {
+				t.Error("Next continues after the end")
+			}
+		})
+	}
+}
+
+func TestRequestIteratorError(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /request", 500, nil)
+	it := c.IterateRequests(nil)
+	if it.Next() || it.Err() == nil {
+		t.Fatal("A failed page is not reported")
+	}
+	if it.Next() || api.count("GET /request") != 1 {
+		t.Error("The iteration continued after an error")
+	}
+}
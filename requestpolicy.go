@@ -0,0 +1,201 @@
+package digicert
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestDecision presents what a RequestPolicy does with a pending request.
+type RequestDecision string
+
+// Request decisions.
+const (
+	RequestApprove  RequestDecision = "approve"
+	RequestReject   RequestDecision = "reject"
+	RequestEscalate RequestDecision = "escalate"
+)
+
+// RequestPolicy presents the rules a pending certificate request must meet to be approved automatically. An empty rule allows anything.
+type RequestPolicy struct {
+	// Products are the allowed product name IDs, e.g. ssl_plus.
+	Products []ProductNameID
+	// Domains are the allowed domains, their subdomains are allowed too.
+	Domains    []string
+	Containers []int
+	// Requesters are the allowed requester emails or user IDs.
+	Requesters       []string
+	MaxValidityYears int
+	// OnViolation is the decision for a request breaking a rule, defaults to RequestEscalate. A request breaking a rule is never approved, RequestApprove escalates it too.
+	OnViolation RequestDecision
+
+	// DryRun only evaluates and logs the decisions.
+	DryRun bool
+	// Log receives one line per evaluated request, when set.
+	Log *log.Logger
+	// Escalate is called with the requests left for a human to process, when set.
+	Escalate func(*ViewRequestResponse, *RequestEvaluation)
+}
+
+// RequestEvaluation presents the decision for a request and, unless in dry run, whether it was applied.
+type RequestEvaluation struct {
+	RequestID int
+	Decision  RequestDecision
+	// Reasons lists the broken rules.
+	Reasons []string
+	Applied bool
+	Err     error
+}
+
+// Evaluate decides on a request from its details. A request that is not pending anymore is escalated, whatever the rules.
+func (p *RequestPolicy) Evaluate(r *ViewRequestResponse) RequestEvaluation {
+	e := RequestEvaluation{RequestID: r.ID, Decision: RequestApprove}
+	if r.Status != RequestPending {
+		e.Decision = RequestEscalate
+		e.Reasons = []string{"the request is " + r.Status.String() + ", not pending"}
+		return e
+	}
+	violation := func(reason string) {
+		e.Reasons = append(e.Reasons, reason)
+	}
+	if r.Order.ID == 0 {
+		violation("the request is not for a certificate order")
+	}
+	if len(p.Products) > 0 {
+		found := false
+		for _, product := range p.Products {
+			found = found || product == r.Order.Product.NameID
+		}
+		if !found {
+			violation("the product " + r.Order.Product.NameID.String() + " is not allowed")
+		}
+	}
+	if len(p.Domains) > 0 {
+		allowed := make(map[string]int)
+		for _, d := range p.Domains {
+			allowed[normalizeHost(d)] = 1
+		}
+		for _, name := range uniqueStrings(append([]string{r.Order.Certificate.CommonName}, r.Order.Certificate.DNSNames...)) {
+			if zone, _ := coveringDomain(allowed, name); zone == "" && name != "" {
+				violation("the domain " + name + " is not allowed")
+			}
+		}
+	}
+	if len(p.Containers) > 0 {
+		found := false
+		for _, id := range p.Containers {
+			found = found || id == r.Order.Container.ID
+		}
+		if !found {
+			violation("the container " + strconv.Itoa(r.Order.Container.ID) + " is not allowed")
+		}
+	}
+	if len(p.Requesters) > 0 {
+		found := false
+		for _, who := range p.Requesters {
+			found = found || strings.EqualFold(who, r.Requester.Email) || who == strconv.Itoa(r.Requester.ID)
+		}
+		if !found {
+			violation("the requester " + r.Requester.Email + " is not allowed")
+		}
+	}
+	if p.MaxValidityYears > 0 && r.Order.ValidityYears > p.MaxValidityYears {
+		violation("the validity of " + strconv.Itoa(r.Order.ValidityYears) + " years is over " + strconv.Itoa(p.MaxValidityYears))
+	}
+	if len(e.Reasons) > 0 {
+		e.Decision = RequestEscalate
+		if p.OnViolation == RequestReject {
+			e.Decision = RequestReject
+		}
+	}
+	return e
+}
+
+// validate refuses a policy without any rule, which would approve anything, and an OnViolation other than the request decisions.
+func (p *RequestPolicy) validate() error {
+	if len(p.Products) == 0 && len(p.Domains) == 0 && len(p.Containers) == 0 && len(p.Requesters) == 0 && p.MaxValidityYears == 0 {
+		return errors.New("The request policy needs at least one rule")
+	}
+	switch p.OnViolation {
+	case "", RequestApprove, RequestReject, RequestEscalate:
+	default:
+		return errors.New("The request decision " + string(p.OnViolation) + " is not accepted")
+	}
+	return nil
+}
+
+// ProcessPendingRequests exports evaluates every pending certificate request against policy, then approves or rejects it with a processor comment, or escalates it.
+// In dry run nothing is updated. A policy without any rule or with an unknown OnViolation is refused.
+func (c *Client) ProcessPendingRequests(policy *RequestPolicy) ([]RequestEvaluation, error) {
+	if policy == nil {
+		return nil, errors.New("The request policy needs at least one rule")
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	// The pending requests are all listed first, as processing them shifts the pages.
+	var pending []ListedRequest
+	it := c.IterateRequests(&RequestFilter{Status: RequestPending})
+	for it.Next() {
+		pending = append(pending, *it.Request())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	var evaluations []RequestEvaluation
+	for _, listed := range pending {
+		requestID := strconv.Itoa(listed.ID)
+		r, err := c.ViewRequest(requestID)
+		if err == nil {
+			err = r.err()
+		}
+		var e RequestEvaluation
+		if err != nil {
+			// The request could not be read, a human has to look at it.
+			e = RequestEvaluation{RequestID: listed.ID, Decision: RequestEscalate, Err: err}
+			if r == nil {
+				r = &ViewRequestResponse{ID: listed.ID, Status: listed.Status}
+			}
+		} else {
+			e = policy.Evaluate(r)
+		}
+		if !policy.DryRun {
+			comment := "Approved by policy on " + time.Now().UTC().Format("2006-01-02")
+			if len(e.Reasons) > 0 {
+				comment = "Rejected by policy: " + strings.Join(e.Reasons, "; ")
+			}
+			switch e.Decision {
+			case RequestApprove:
+				e.Applied, e.Err = c.ApproveRequest(requestID, comment)
+			case RequestReject:
+				e.Applied, e.Err = c.RejectRequest(requestID, comment)
+			case RequestEscalate:
+				if policy.Escalate != nil {
+					policy.Escalate(r, &e)
+				}
+			}
+		}
+		if policy.Log != nil {
+			policy.Log.Println(e.logLine(policy.DryRun))
+		}
+		evaluations = append(evaluations, e)
+	}
+	return evaluations, nil
+}
+
+// logLine describes the evaluation for the policy log.
+func (e *RequestEvaluation) logLine(dryRun bool) string {
+	s := "request " + strconv.Itoa(e.RequestID) + ": " + string(e.Decision)
+	if dryRun {
+		s += " (dry run)"
+	}
+	if len(e.Reasons) > 0 {
+		s += ", " + strings.Join(e.Reasons, "; ")
+	}
+	if e.Err != nil {
+		s += ", error: " + e.Err.Error()
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package digicert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessPendingRequests(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /request", 200, map[string]interface{}{
+		"requests": []map[string]interface{}{{"id": 1, "status": "pending"}, {"id": 2, "status": "pending"}, {"id": 3, "status": "pending"}},
+		"page":     map[string]int{"total": 3},
+	})
+	order := map[string]interface{}{"id": 10, "product": map[string]string{"name_id": "ssl_plus"}, "certificate": map[string]string{"common_name": "example.com"}}
+	api.reply("GET /request/1", 200, map[string]interface{}{"id": 1, "status": "pending", "order": order})
+	// The request was processed by someone else since it was listed.
+	api.reply("GET /request/2", 200, map[string]interface{}{"id": 2, "status": "approved", "order": order})
+	api.reply("GET /request/3", 500, nil)
+	api.reply("PUT /request/1/status", 204, nil)
+
+	escalated := make(map[int]*RequestEvaluation)
+	policy := &RequestPolicy{
+		Products: []ProductNameID{"ssl_plus"},
+		Escalate: func(r *ViewRequestResponse, e *RequestEvaluation) {
+			if r == nil || r.ID != e.RequestID {
+				t.Errorf("Escalate got request %+v for evaluation %+v", r, e)
+			}
+			escalated[e.RequestID] = e
+		},
+	}
+	evaluations, err := c.ProcessPendingRequests(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evaluations) != 3 {
+		t.Fatalf("evaluations = %+v", evaluations)
+	}
+	if e := evaluations[0]; e.Decision != RequestApprove || !e.Applied {
+		t.Errorf("request 1: %+v", e)
+	}
+	if e := evaluations[1]; e.Decision != RequestEscalate || escalated[2] == nil {
+		t.Errorf("request 2 is not escalated: %+v", e)
+	}
+	if e := evaluations[2]; e.Decision != RequestEscalate || e.Err == nil || escalated[3] == nil {
+		t.Errorf("request 3 is not escalated: %+v", e)
+	}
+	if n := api.count("PUT /request/2/status"); n != 0 {
+		t.Error("A request no longer pending was updated")
+	}
+	if q := api.requests()[0]; q != "GET /request?filters%5Bstatus%5D=pending&limit=100" {
+		t.Errorf("The pending requests were listed with %s", q)
+	}
+}
+
+func TestRequestPolicyOnViolation(t *testing.T) {
+	_, c := newFakeAPI(t)
+	for _, decision := range []RequestDecision{"rejct", "Reject", "deny"} {
+		_, err := c.ProcessPendingRequests(&RequestPolicy{Products: []ProductNameID{ProductSSLPlus}, OnViolation: decision})
+		if err == nil || !strings.Contains(err.Error(), string(decision)) {
+			t.Errorf("ProcessPendingRequests with OnViolation %q = %v, want an error", decision, err)
+		}
+	}
+	if _, err := c.ProcessPendingRequests(&RequestPolicy{OnViolation: RequestReject}); err == nil {
+		t.Error("ProcessPendingRequests accepted a policy without rules")
+	}
+
+	r := &ViewRequestResponse{ID: 1, Status: RequestPending}
+	r.Order.ID = 10
+	r.Order.Product.NameID = ProductSSL
+	tests := []struct {
+		onViolation RequestDecision
+		want        RequestDecision
+	}{
+		{"", RequestEscalate},
+		{RequestEscalate, RequestEscalate},
+		{RequestReject, RequestReject},
+		{RequestApprove, RequestEscalate},
+	}
+	for _, tt := range tests {
+		p := &RequestPolicy{Products: []ProductNameID{ProductSSLPlus}, OnViolation: tt.onViolation}
+		if err := p.validate(); err != nil {
+			t.Errorf("validate(%q) = %v", tt.onViolation, err)
+		}
+		if e := p.Evaluate(r); e.Decision != tt.want {
+			t.Errorf("OnViolation %q decided %s, want %s", tt.onViolation, e.Decision, tt.want)
+		}
+	}
+}
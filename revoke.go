@@ -0,0 +1,187 @@
+package digicert
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BulkRevokeOptions presents how BulkRevoke revokes the resolved certificates.
+type BulkRevokeOptions struct {
+	Comment          string
+	RevocationReason RevocationReason
+	SkipApproval     bool
+	// WholeOrder revokes the order of every matched certificate instead of the single certificate.
+	WholeOrder bool
+	RateLimit
+}
+
+// BulkRevokeResult presents the outcome of revoking one serial number or thumbprint.
+type BulkRevokeResult struct {
+	Item          string
+	OrderID       int
+	CertificateID int
+	Response      *RevokeCertificateResponse
+	Err           error
+}
+
+// BulkRevoke exports to revoke a list of certificates given by serial number or thumbprint, e.g. after a key compromise.
+// The items are resolved to certificate IDs by walking the issued orders with their duplicates and reissues, then revoked concurrently, one result is returned per item in the input order.
+func (c *Client) BulkRevoke(items []string, options *BulkRevokeOptions) ([]BulkRevokeResult, error) {
+	if options == nil {
+		options = new(BulkRevokeOptions)
+	}
+	if err := options.RevocationReason.validate(); err != nil {
+		return nil, err
+	}
+	concurrency, interval := options.limits()
+
+	results := make([]BulkRevokeResult, len(items))
+	wanted := make(map[string]bool)
+	for i, item := range items {
+		results[i].Item = item
+		if key := normalizeHex(item); key != "" {
+			wanted[key] = true
+		}
+	}
+	found, err := c.resolveCertificates(wanted, concurrency, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &RevokeCertificateRequest{
+		Comment:          options.Comment,
+		RevocationReason: options.RevocationReason,
+		SkipApproval:     options.SkipApproval,
+	}
+	// several items may share an order, with WholeOrder it is revoked only once.
+	var targets []int
+	first := make(map[int]int)
+	for i := range results {
+		r := &results[i]
+		cert, ok := found[normalizeHex(r.Item)]
+		if !ok {
+			r.Err = errors.New("The certificate was not found in the issued orders")
+			continue
+		}
+		r.OrderID, r.CertificateID = cert.orderID, cert.certificateID
+		if options.WholeOrder {
+			if _, ok := first[r.OrderID]; ok {
+				continue
+			}
+			first[r.OrderID] = i
+		}
+		targets = append(targets, i)
+	}
+	throttle(len(targets), concurrency, interval, func(t int) {
+		r := &results[targets[t]]
+		var err error
+		if options.WholeOrder {
+			r.Response, err = c.clone().RevokeOrder(strconv.Itoa(r.OrderID), request)
+		} else {
+			r.Response, err = c.clone().RevokeCertificate(strconv.Itoa(r.CertificateID), request)
+		}
+		if err == nil {
+			err = r.Response.err()
+		}
+		r.Err = err
+	})
+	if options.WholeOrder {
+		for i := range results {
+			r := &results[i]
+			if j, ok := first[r.OrderID]; ok && j != i && r.Err == nil {
+				r.Response, r.Err = results[j].Response, results[j].Err
+			}
+		}
+	}
+	return results, nil
+}
+
+type resolvedCertificate struct {
+	orderID       int
+	certificateID int
+}
+
+// resolveCertificates pages through the issued orders, with their duplicates and reissues, until every wanted serial number or thumbprint is found.
+func (c *Client) resolveCertificates(wanted map[string]bool, workers int, interval time.Duration) (map[string]resolvedCertificate, error) {
+	found := make(map[string]resolvedCertificate)
+	var mu sync.Mutex
+	var firstErr error
+	err := c.clone().eachOrderPage(func(page *ListOrders) bool {
+		var ids []string
+		for _, o := range page.Orders {
+			if o.Status == OrderIssued {
+				ids = append(ids, strconv.Itoa(o.ID))
+			}
+		}
+		throttle(len(ids), workers, interval, func(i int) {
+			certs, err := c.clone().orderCertificates(ids[i])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for _, cert := range certs {
+				for _, key := range []string{normalizeHex(cert.serialNumber), normalizeHex(cert.thumbprint)} {
+					if wanted[key] {
+						found[key] = cert.resolvedCertificate
+					}
+				}
+			}
+		})
+		return firstErr == nil && len(found) < len(wanted)
+	})
+	if err == nil {
+		err = firstErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+type orderCertificate struct {
+	resolvedCertificate
+	serialNumber string
+	thumbprint   string
+}
+
+// orderCertificates returns the current certificate of an order with its duplicates and earlier reissues, a compromised key may be in any of them.
+func (c *Client) orderCertificates(orderID string) ([]orderCertificate, error) {
+	order, err := c.ViewOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := order.err(); err != nil {
+		return nil, err
+	}
+	certs := []orderCertificate{{resolvedCertificate{order.ID, order.Certificate.ID}, order.Certificate.SerialNumber, order.Certificate.Thumbprint}}
+	duplicates, err := c.ListDuplicateCertificates(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := duplicates.err(); err != nil {
+		return nil, err
+	}
+	reissues, err := c.ListReissueCertificates(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := reissues.err(); err != nil {
+		return nil, err
+	}
+	for _, cert := range append(duplicates.Certificates, reissues.Certificates...) {
+		certs = append(certs, orderCertificate{resolvedCertificate{order.ID, cert.ID}, cert.SerialNumber, cert.Thumbprint})
+	}
+	return certs, nil
+}
+
+// normalizeHex lowercases a serial number or thumbprint and strips the usual separators.
+func normalizeHex(s string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "", "-", "").Replace(strings.TrimSpace(s)))
+}
@@ -0,0 +1,131 @@
+package digicert
+
+import (
+	"testing"
+)
+
+// revokeFixture registers two issued orders and a pending one:
+// order 1 has the certificate 10 and its duplicate 11, order 2 the certificate 20 and an earlier reissue 21.
+func revokeFixture(api *fakeAPI) {
+	api.reply("GET /order/certificate/", 200, map[string]interface{}{
+		"orders": []map[string]interface{}{{"id": 1, "status": "issued"}, {"id": 2, "status": "issued"}, {"id": 3, "status": "pending"}},
+		"page":   map[string]int{"total": 3},
+	})
+	api.reply("GET /order/certificate/1", 200, map[string]interface{}{"id": 1, "certificate": map[string]interface{}{"id": 10, "serial_number": "0ABC", "thumbprint": "AA11"}})
+	api.reply("GET /order/certificate/1/duplicate", 200, map[string]interface{}{"certificates": []map[string]interface{}{{"id": 11, "serial_number": "0DDD"}}})
+	api.reply("GET /order/certificate/1/reissue", 200, map[string]interface{}{"certificates": []interface{}{}})
+	api.reply("GET /order/certificate/2", 200, map[string]interface{}{"id": 2, "certificate": map[string]interface{}{"id": 20, "serial_number": "0EEE"}})
+	api.reply("GET /order/certificate/2/duplicate", 200, map[string]interface{}{"certificates": []interface{}{}})
+	api.reply("GET /order/certificate/2/reissue", 200, map[string]interface{}{"certificates": []map[string]interface{}{{"id": 21, "serial_number": "0FFF", "thumbprint": "BB22"}}})
+}
+
+func TestBulkRevoke(t *testing.T) {
+	api, c := newFakeAPI(t)
+	revokeFixture(api)
+	api.reply("PUT /certificate/10/revoke", 201, map[string]interface{}{"id": 100, "status": "pending"})
+	api.reply("PUT /certificate/21/revoke", 201, map[string]interface{}{"id": 121, "status": "approved"})
+	// The duplicate cannot be revoked, the other items are still revoked.
+	api.reply("PUT /certificate/11/revoke", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_certificate", "message": "The certificate is already revoked."}}})
+
+	items := []string{"0a:bc", "0DDD", "bb 22", "1234"}
+	results, err := c.BulkRevoke(items, &BulkRevokeOptions{Comment: "key compromise", RevocationReason: "key_compromise", RateLimit: RateLimit{Interval: -1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		orderID, certificateID, requestID int
+		failed                            bool
+	}{{1, 10, 100, false}, {1, 11, 0, true}, {2, 21, 121, false}, {0, 0, 0, true}}
+	for i, w := range want {
+		r := results[i]
+		if r.Item != items[i] || r.OrderID != w.orderID || r.CertificateID != w.certificateID || (r.Err != nil) != w.failed {
+			t.Errorf("result %d = %+v, want %+v", i, r, w)
+		}
+		if !w.failed && (r.Response == nil || r.Response.ID != w.requestID) {
+			t.Errorf("result %d response = %+v", i, r.Response)
+		}
+	}
+	var body map[string]interface{}
+	api.body("PUT /certificate/10/revoke", &body)
+	if body["comments"] != "key compromise" || body["revocation_reason"] != "key_compromise" {
+		t.Errorf("The revoke request sent %v", body)
+	}
+	if api.count("GET /order/certificate/3") != 0 {
+		t.Error("A pending order was resolved")
+	}
+}
+
+func TestBulkRevokeWholeOrder(t *testing.T) {
+	api, c := newFakeAPI(t)
+	revokeFixture(api)
+	api.reply("PUT /order/certificate/1/revoke", 201, map[string]interface{}{"id": 101, "status": "pending"})
+
+	results, err := c.BulkRevoke([]string{"AA11", "0ddd"}, &BulkRevokeOptions{WholeOrder: true, RateLimit: RateLimit{Interval: -1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Err != nil || r.OrderID != 1 || r.Response == nil || r.Response.ID != 101 {
+			t.Errorf("result = %+v", r)
+		}
+	}
+	if n := api.count("PUT /order/certificate/1/revoke"); n != 1 {
+		t.Errorf("The order was revoked %d times", n)
+	}
+}
+
+func TestBulkRevokeInvalidReason(t *testing.T) {
+	_, c := newFakeAPI(t)
+	if _, err := c.BulkRevoke([]string{"0abc"}, &BulkRevokeOptions{RevocationReason: "lost"}); err == nil {
+		t.Error("An unknown revocation reason is accepted")
+	}
+}
+
+func TestRevokeOrder(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /order/certificate/7/revoke", 201, map[string]interface{}{"id": 70, "status": "pending", "comments": "retired"})
+	res, err := c.RevokeOrder("7", &RevokeCertificateRequest{Comment: "retired", SkipApproval: true})
+	if err != nil || res.ID != 70 || res.Status != RequestPending {
+		t.Fatalf("RevokeOrder = %+v, %v", res, err)
+	}
+	var body map[string]interface{}
+	api.body("PUT /order/certificate/7/revoke", &body)
+	if body["comments"] != "retired" || body["skip_approval"] != true {
+		t.Errorf("The revoke request sent %v", body)
+	}
+	if _, ok := body["revocation_reason"]; ok {
+		t.Error("An empty revocation reason is sent")
+	}
+}
+
+func TestListReissueCertificates(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /order/certificate/7/reissue", 200, map[string]interface{}{"certificates": []map[string]interface{}{
+		{"id": 71, "serial_number": "0A", "status": "revoked"},
+		{"id": 72, "serial_number": "0B", "status": "issued"},
+	}})
+	res, err := c.ListReissueCertificates("7")
+	if err != nil || res.err() != nil {
+		t.Fatalf("ListReissueCertificates = %+v, %v", res, err)
+	}
+	if len(res.Certificates) != 2 || res.Certificates[0].ID != 71 || res.Certificates[1].Status != OrderIssued {
+		t.Errorf("Certificates = %+v", res.Certificates)
+	}
+}
+
+func TestRateLimitLimits(t *testing.T) {
+	for _, test := range []struct {
+		limit       RateLimit
+		concurrency int
+		interval    string
+	}{
+		{RateLimit{}, 4, "200ms"},
+		{RateLimit{Concurrency: 8, Interval: 1e9}, 8, "1s"},
+		{RateLimit{Interval: -1}, 4, "0s"},
+	} {
+		concurrency, interval := test.limit.limits()
+		if concurrency != test.concurrency || interval.String() != test.interval {
+			t.Errorf("%+v: limits = %d, %s", test.limit, concurrency, interval)
+		}
+	}
+}
@@ -2,9 +2,40 @@ package digicert
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
+	"net/url"
+	"strconv"
 )
 
+// ErrUserNameTaken is returned by UserNameAvailable when the username is in use.
+var ErrUserNameTaken = errors.New("The username is already taken")
+
+// UserError presents a failed user call, StatusCode is the HTTP status of the response, 0 when there was none.
+type UserError struct {
+	Op         string
+	UserID     string
+	StatusCode int
+	Err        error
+}
+
+func (e *UserError) Error() string {
+	if e.UserID == "" {
+		return "user " + e.Op + ": " + e.Err.Error()
+	}
+	return "user " + e.UserID + " " + e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *UserError) Unwrap() error {
+	return e.Err
+}
+
+// NotFound reports whether the user does not exist or is not visible to the API key.
+func (e *UserError) NotFound() bool {
+	return e.StatusCode == 404
+}
+
 // CheckUserNameResponse presents checking username available.
 type CheckUserNameResponse struct {
 	Available bool `json:"available"`
@@ -13,20 +44,34 @@ type CheckUserNameResponse struct {
 }
 
 // CheckUserName exports Use this endpoint to check to see if the specified username is available.
+//
+// Deprecated: use UserNameAvailable, which tells a taken username from a failed call.
 func (c *Client) CheckUserName(username string) bool {
+	available, err := c.UserNameAvailable(username)
+	if err != nil && err != ErrUserNameTaken {
+		log.Println("err", err)
+	}
+	return available
+}
+
+// UserNameAvailable exports Use this endpoint to check to see if the specified username is available.
+// It returns ErrUserNameTaken when the username is in use and a *UserError when the call fails. It replaces CheckUserName.
+func (c *Client) UserNameAvailable(username string) (bool, error) {
 	var check CheckUserNameResponse
-	data, err := c.makeRequest("GET", "/user/availability/"+username, nil)
+	data, err := c.makeRequest("GET", "/user/availability/"+url.PathEscape(username), nil)
 	if err != nil {
-		log.Println("err", err)
-		return false
+		return false, c.userError("check username", "", err)
 	}
 	if err := json.Unmarshal(data, &check); err != nil {
-		return false
+		return false, c.userError("check username", "", err)
+	}
+	if err := check.err(); err != nil {
+		return false, c.userError("check username", "", err)
 	}
-	if c.statusCode == 200 && check.Available == true {
-		return true
+	if !check.Available {
+		return false, ErrUserNameTaken
 	}
-	return false
+	return true, nil
 }
 
 // ListRolesResponse exports available roles
@@ -113,6 +158,16 @@ type UpdateUserRequest struct {
 	Telephone string `json:"telephone"`
 }
 
+// UserStatusRequest presents update user status request, status only can be set to active or inactive
+type UserStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// UserContainerAssignmentsRequest presents the containers assigned to a user
+type UserContainerAssignmentsRequest struct {
+	ContainerIDAssignments []int `json:"container_id_assignments"`
+}
+
 // UpdateUserRoleRequest presents update user role request
 type UpdateUserRoleRequest struct {
 	AccessRoles []struct {
@@ -120,31 +175,35 @@ type UpdateUserRoleRequest struct {
 	} `json:"access_roles"`
 }
 
+// ListedUser presents a user of ListUsers
+type ListedUser struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	JobTitle  string `json:"job_title"`
+	Telephone string `json:"telephone"`
+	Status    string `json:"status"`
+	Container struct {
+		ID         int    `json:"id"`
+		PublicID   string `json:"public_id"`
+		Name       string `json:"name"`
+		ParentID   int    `json:"parent_id"`
+		TemplateID int    `json:"template_id"`
+		HasLogo    bool   `json:"has_logo"`
+		IsActive   bool   `json:"is_active"`
+	} `json:"container"`
+	AccessRoles []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"access_roles"`
+	HasContainerAssignments bool `json:"has_container_assignments"`
+}
+
 // ListUsersResponse presents all of users
 type ListUsersResponse struct {
-	Users []struct {
-		ID        int    `json:"id"`
-		Username  string `json:"username"`
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
-		Email     string `json:"email"`
-		JobTitle  string `json:"job_title"`
-		Status    string `json:"status"`
-		Container struct {
-			ID         int    `json:"id"`
-			PublicID   string `json:"public_id"`
-			Name       string `json:"name"`
-			ParentID   int    `json:"parent_id"`
-			TemplateID int    `json:"template_id"`
-			HasLogo    bool   `json:"has_logo"`
-			IsActive   bool   `json:"is_active"`
-		} `json:"container"`
-		AccessRoles []struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		} `json:"access_roles"`
-		HasContainerAssignments bool `json:"has_container_assignments"`
-	} `json:"users"`
+	Users []ListedUser `json:"users"`
 
 	SchemeValidationErrors
 }
@@ -177,15 +236,17 @@ func (c *Client) NewUser(request *NewUserRequest) (*NewUserResponse, error) {
 }
 
 // ResendCreateUserEmail exports Use this endpoint to resend a create user email to a specific user.
+//
+// Deprecated: use ResendCreateEmail, which returns why the email was not sent.
 func (c *Client) ResendCreateUserEmail(userID string) bool {
-	_, err := c.makeRequest("GET", "/user/"+userID+"/resend-create-email/", nil)
-	if err != nil {
-		return false
-	}
-	if c.statusCode == 204 {
-		return true
-	}
-	return false
+	ok, _ := c.ResendCreateEmail(userID)
+	return ok
+}
+
+// ResendCreateEmail exports Use this endpoint to resend a create user email to a specific user. It replaces ResendCreateUserEmail and returns a *UserError when the call fails.
+func (c *Client) ResendCreateEmail(userID string) (bool, error) {
+	data, err := c.makeRequest("GET", "/user/"+userID+"/resend-create-email/", nil)
+	return c.userResult("resend create email", userID, data, err)
 }
 
 // ViewUser exports Use this endpoint to view the specified user.
@@ -208,41 +269,114 @@ func (c *Client) ViewUser(userID string) (*UserResponse, error) {
 // email	Required	[string]
 // job_title	Optional	[string]		This is required for to allow the User to be an approver for Extended Validation certificates
 // telephone	Optional	[string]		This is required for to allow the User to be an approver for Extended Validation certificates
+//
+// Deprecated: use EditUser, which returns why the user was not updated.
 func (c *Client) UpdateUser(userID string, request *UpdateUserRequest) bool {
+	ok, _ := c.EditUser(userID, request)
+	return ok
+}
+
+// EditUser exports Use this endpoint to update the specified user, with the fields of UpdateUser. It replaces UpdateUser and returns a *UserError when the call fails.
+func (c *Client) EditUser(userID string, request *UpdateUserRequest) (bool, error) {
 	c.request = request
-	_, err := c.makeRequest("PUT", "/user/"+userID, nil)
-	if err != nil {
-		return false
-	}
-	if c.statusCode == 204 {
-		return true
-	}
-	return false
+	data, err := c.makeRequest("PUT", "/user/"+userID, nil)
+	return c.userResult("update", userID, data, err)
 }
 
 // UpdateUserRole exports Use this endpoint to update the access roles of the specified user.
+//
+// Deprecated: use EditUserRole, which returns why the roles were not updated.
 func (c *Client) UpdateUserRole(userID string, request *UpdateUserRoleRequest) bool {
+	ok, _ := c.EditUserRole(userID, request)
+	return ok
+}
+
+// EditUserRole exports Use this endpoint to update the access roles of the specified user. It replaces UpdateUserRole and returns a *UserError when the call fails.
+func (c *Client) EditUserRole(userID string, request *UpdateUserRoleRequest) (bool, error) {
 	c.request = request
-	_, err := c.makeRequest("PUT", "/user/"+userID+"/role", nil)
+	data, err := c.makeRequest("PUT", "/user/"+userID+"/role", nil)
+	return c.userResult("update role", userID, data, err)
+}
+
+// DeleteUser exports Use this endpoint with the DELETE method to delete the specified user.
+//
+// Deprecated: use RemoveUser, which returns why the user was not deleted.
+func (c *Client) DeleteUser(userID string) bool {
+	ok, _ := c.RemoveUser(userID)
+	return ok
+}
+
+// RemoveUser exports Use this endpoint with the DELETE method to delete the specified user. It replaces DeleteUser and returns a *UserError when the call fails.
+func (c *Client) RemoveUser(userID string) (bool, error) {
+	data, err := c.makeRequest("DELETE", "/user/"+userID, nil)
+	return c.userResult("delete", userID, data, err)
+}
+
+// ActivateUser exports Use this endpoint to activate a user that was previously deactivated.
+func (c *Client) ActivateUser(userID string) (bool, error) {
+	c.request = &UserStatusRequest{Status: "active"}
+	data, err := c.makeRequest("PUT", "/user/"+userID+"/status", nil)
+	return c.userResult("activate", userID, data, err)
+}
+
+// DeactivateUser exports Use this endpoint to deactivate a user, the user can no longer sign in nor use its API keys.
+func (c *Client) DeactivateUser(userID string) (bool, error) {
+	c.request = &UserStatusRequest{Status: "inactive"}
+	data, err := c.makeRequest("PUT", "/user/"+userID+"/status", nil)
+	return c.userResult("deactivate", userID, data, err)
+}
+
+// UpdateUserContainerAssignments exports Use this endpoint to replace the containers a user is assigned to, besides its own container.
+func (c *Client) UpdateUserContainerAssignments(userID string, containerIDs []int) (bool, error) {
+	c.request = &UserContainerAssignmentsRequest{ContainerIDAssignments: containerIDs}
+	data, err := c.makeRequest("PUT", "/user/"+userID+"/container-assignment", nil)
+	return c.userResult("update container assignments", userID, data, err)
+}
+
+// ListUserAPIKeys exports to retrieve the API Keys of the specified user.
+func (c *Client) ListUserAPIKeys(userID string) (*ListAPIKeysResponse, error) {
+	c.result = new(ListAPIKeysResponse)
+	data, err := c.makeRequest("GET", "/key?"+url.Values{"filters[user_id]": {userID}}.Encode(), nil)
 	if err != nil {
-		return false
+		return nil, c.userError("list api keys", userID, err)
 	}
-	if c.statusCode == 204 {
-		return true
+	if err := json.Unmarshal(data, &c.result); err != nil {
+		return nil, err
+	}
+	keys := c.result.(*ListAPIKeysResponse)
+	if err := keys.err(); err != nil {
+		return nil, c.userError("list api keys", userID, err)
+	}
+	// The filter is applied again in case the API ignores it.
+	all := keys.APIKeys
+	keys.APIKeys = all[:0]
+	for _, k := range all {
+		if strconv.Itoa(k.User.ID) == userID {
+			keys.APIKeys = append(keys.APIKeys, k)
+		}
 	}
-	return false
+	return keys, nil
 }
 
-// DeleteUser exports Use this endpoint with the DELETE method to delete the specified user.
-func (c *Client) DeleteUser(userID string) bool {
-	_, err := c.makeRequest("DELETE", "/user/"+userID, nil)
+// userResult turns the outcome of a user call answering 204 into (true, nil) or a *UserError.
+func (c *Client) userResult(op, userID string, data []byte, err error) (bool, error) {
 	if err != nil {
-		return false
+		return false, c.userError(op, userID, err)
 	}
 	if c.statusCode == 204 {
-		return true
+		return true, nil
+	}
+	var res SchemeValidationErrors
+	if err := json.Unmarshal(data, &res); err == nil {
+		if err := res.err(); err != nil {
+			return false, c.userError(op, userID, err)
+		}
 	}
-	return false
+	return false, c.userError(op, userID, errors.New("Unexpected status "+strconv.Itoa(c.statusCode)))
+}
+
+func (c *Client) userError(op, userID string, err error) error {
+	return &UserError{Op: op, UserID: userID, StatusCode: c.statusCode, Err: err}
 }
 
 // ListUsers exports Use this endpoint to retrieve a list of users in the current container and all child containers or from the specified container.
@@ -0,0 +1,144 @@
+package digicert
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUserNameAvailable(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /user/availability/ada", 200, map[string]bool{"available": true})
+	api.reply("GET /user/availability/grace", 200, map[string]bool{"available": false})
+	api.reply("GET /user/availability/bad name", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_username", "message": "Invalid username."}}})
+
+	if ok, err := c.UserNameAvailable("ada"); !ok || err != nil {
+		t.Errorf("UserNameAvailable(ada) = %v, %v", ok, err)
+	}
+	if ok, err := c.UserNameAvailable("grace"); ok || err != ErrUserNameTaken {
+		t.Errorf("UserNameAvailable(grace) = %v, %v, want ErrUserNameTaken", ok, err)
+	}
+	ok, err := c.UserNameAvailable("bad name")
+	var userErr *UserError
+	if ok || !errors.As(err, &userErr) || userErr.StatusCode != 400 || userErr.Op != "check username" {
+		t.Fatalf("UserNameAvailable(bad name) = %v, %v, want a *UserError", ok, err)
+	}
+	if userErr.Error() != "user check username: invalid_username: Invalid username." {
+		t.Errorf("Error() = %q", userErr.Error())
+	}
+	if c.CheckUserName("grace") || !c.CheckUserName("ada") {
+		t.Error("CheckUserName does not report the availability")
+	}
+}
+
+func TestUserCallErrors(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /user/7", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_email", "message": "Invalid email."}}})
+	api.reply("PUT /user/7/role", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_role", "message": "Invalid role."}}})
+	api.reply("GET /user/7/resend-create-email", 200, map[string]interface{}{})
+	api.reply("DELETE /user/8", 404, nil)
+
+	ok, err := c.EditUser("7", &UpdateUserRequest{Username: "ada", Email: "ada"})
+	var userErr *UserError
+	if ok || !errors.As(err, &userErr) || userErr.StatusCode != 400 || userErr.UserID != "7" || userErr.NotFound() {
+		t.Fatalf("EditUser = %v, %v, want a *UserError", ok, err)
+	}
+	if userErr.Error() != "user 7 update: invalid_email: Invalid email." {
+		t.Errorf("Error() = %q", userErr.Error())
+	}
+	if ok, err := c.EditUserRole("7", new(UpdateUserRoleRequest)); ok || err == nil || !errors.As(err, &userErr) || userErr.Op != "update role" {
+		t.Errorf("EditUserRole = %v, %v, want a *UserError", ok, err)
+	}
+	// A status other than 204 without an error body still fails.
+	if ok, err := c.ResendCreateEmail("7"); ok || !errors.As(err, &userErr) || userErr.StatusCode != 200 {
+		t.Errorf("ResendCreateEmail = %v, %v, want a *UserError", ok, err)
+	}
+	if ok, err := c.RemoveUser("8"); ok || !errors.As(err, &userErr) || !userErr.NotFound() {
+		t.Errorf("RemoveUser = %v, %v, want a not found *UserError", ok, err)
+	}
+	if c.UpdateUser("7", &UpdateUserRequest{}) || c.UpdateUserRole("7", new(UpdateUserRoleRequest)) || c.ResendCreateUserEmail("7") || c.DeleteUser("8") {
+		t.Error("A deprecated call reports success after an error")
+	}
+}
+
+func TestUserCallsSucceed(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /user/7", 204, nil)
+	api.reply("PUT /user/7/status", 204, nil)
+	api.reply("DELETE /user/7", 204, nil)
+
+	if ok, err := c.EditUser("7", &UpdateUserRequest{Username: "ada"}); !ok || err != nil {
+		t.Errorf("EditUser = %v, %v", ok, err)
+	}
+	if ok, err := c.DeactivateUser("7"); !ok || err != nil {
+		t.Errorf("DeactivateUser = %v, %v", ok, err)
+	}
+	var status UserStatusRequest
+	api.body("PUT /user/7/status", &status)
+	if status.Status != "inactive" {
+		t.Errorf("The status sent is %q", status.Status)
+	}
+	if !c.DeleteUser("7") {
+		t.Error("DeleteUser = false")
+	}
+}
+
+func TestUserStatusAndAssignments(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("PUT /user/7/status", 204, nil)
+	api.reply("PUT /user/7/container-assignment", 204, nil)
+	api.reply("PUT /user/8/container-assignment", 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_container", "message": "Invalid container."}}})
+
+	if ok, err := c.ActivateUser("7"); !ok || err != nil {
+		t.Errorf("ActivateUser = %v, %v", ok, err)
+	}
+	var status UserStatusRequest
+	api.body("PUT /user/7/status", &status)
+	if status.Status != "active" {
+		t.Errorf("The status sent is %q", status.Status)
+	}
+	if ok, err := c.UpdateUserContainerAssignments("7", []int{2, 3}); !ok || err != nil {
+		t.Errorf("UpdateUserContainerAssignments = %v, %v", ok, err)
+	}
+	var assignments UserContainerAssignmentsRequest
+	api.body("PUT /user/7/container-assignment", &assignments)
+	if !reflect.DeepEqual(assignments.ContainerIDAssignments, []int{2, 3}) {
+		t.Errorf("The assignments sent are %v", assignments.ContainerIDAssignments)
+	}
+	ok, err := c.UpdateUserContainerAssignments("8", []int{99})
+	var userErr *UserError
+	if ok || !errors.As(err, &userErr) || userErr.Op != "update container assignments" || userErr.UserID != "8" {
+		t.Errorf("UpdateUserContainerAssignments = %v, %v, want a *UserError", ok, err)
+	}
+}
+
+func TestListUserAPIKeys(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("GET /key", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("filters[user_id]") {
+		case "7":
+			// The keys of another user are dropped in case the API ignores the filter.
+			writeJSON(w, 200, map[string]interface{}{"api_keys": []map[string]interface{}{
+				{"id": 1, "name": "ci", "user": map[string]int{"id": 7}},
+				{"id": 2, "name": "other", "user": map[string]int{"id": 8}},
+			}})
+		default:
+			writeJSON(w, 400, map[string]interface{}{"errors": []map[string]string{{"code": "invalid_filter", "message": "Invalid filter."}}})
+		}
+	})
+
+	keys, err := c.ListUserAPIKeys("7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys.APIKeys) != 1 || keys.APIKeys[0].ID != 1 {
+		t.Errorf("ListUserAPIKeys = %+v", keys.APIKeys)
+	}
+	keys, err = c.ListUserAPIKeys("x")
+	var userErr *UserError
+	if keys != nil || !errors.As(err, &userErr) || userErr.Op != "list api keys" || !strings.Contains(err.Error(), "invalid_filter") {
+		t.Errorf("ListUserAPIKeys = %+v, %v, want a *UserError", keys, err)
+	}
+}
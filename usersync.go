@@ -0,0 +1,364 @@
+package digicert
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DesiredUser presents a user as it should exist in CertCentral, e.g. from an HR or IdP export.
+type DesiredUser struct {
+	Username    string `json:"username"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Email       string `json:"email"`
+	JobTitle    string `json:"job_title,omitempty"`
+	Telephone   string `json:"telephone,omitempty"`
+	ContainerID int    `json:"container_id,omitempty"`
+	// Roles are access role names or IDs, as listed by ListRoles. Without roles the roles of an existing user are left as they are.
+	Roles []string `json:"roles"`
+}
+
+// UserSyncAction presents what SyncUsers does to a user.
+type UserSyncAction string
+
+// User sync actions.
+const (
+	UserSyncCreate    UserSyncAction = "create"
+	UserSyncUpdate    UserSyncAction = "update"
+	UserSyncDelete    UserSyncAction = "delete"
+	UserSyncUnchanged UserSyncAction = "unchanged"
+)
+
+// UserSyncResult presents the planned action for one user and, once applied, its outcome.
+type UserSyncResult struct {
+	Username string
+	Action   UserSyncAction
+	// UserID is the existing user, or the created one once applied.
+	UserID int
+	// Changes names what differs: first_name, last_name, email, job_title, telephone, roles.
+	Changes []string
+	Applied bool
+	Err     error
+
+	desired  *DesiredUser
+	existing *ListedUser
+	roleIDs  []int
+}
+
+// UserSyncOptions presents how SyncUsers reconciles the users of a container.
+type UserSyncOptions struct {
+	// ContainerID is the container whose users (with its children) are synced, and the default container of new users.
+	ContainerID string
+	// DryRun only plans the actions.
+	DryRun bool
+	// Delete removes the users missing from the desired list, except those in Keep.
+	Delete bool
+	Keep   []string
+	RateLimit
+}
+
+// SyncUsers exports to keep the CertCentral users in line with a desired list: it diffs the list against ListUsers and ListRoles and plans creates, updates, role changes and deletions.
+// With DryRun the plan is returned as is, otherwise it is applied and every result reports whether it was. Users are matched by username, case-insensitively.
+func (c *Client) SyncUsers(desired []DesiredUser, options *UserSyncOptions) ([]UserSyncResult, error) {
+	if options == nil || options.ContainerID == "" {
+		return nil, errors.New("The container of the users must input")
+	}
+	defaultContainer, err := strconv.Atoi(options.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := c.clone().ListUsers(options.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := users.err(); err != nil {
+		return nil, err
+	}
+
+	// roles maps a container to its role IDs by lowercased name.
+	roles := make(map[int]map[string]int)
+	rolesOf := func(containerID int) (map[string]int, error) {
+		if r, ok := roles[containerID]; ok {
+			return r, nil
+		}
+		list, err := c.clone().ListRoles(strconv.Itoa(containerID))
+		if err != nil {
+			return nil, err
+		}
+		if err := list.err(); err != nil {
+			return nil, err
+		}
+		r := make(map[string]int)
+		for _, role := range list.AccessRoles {
+			r[strings.ToLower(role.Name)] = role.ID
+		}
+		roles[containerID] = r
+		return r, nil
+	}
+
+	existing := make(map[string]int)
+	for i, u := range users.Users {
+		existing[strings.ToLower(u.Username)] = i
+	}
+	var results []UserSyncResult
+	wanted := make(map[string]bool)
+	for i := range desired {
+		// d is a copy, the caller's list is left as it is.
+		d := desired[i]
+		if d.ContainerID == 0 {
+			d.ContainerID = defaultContainer
+		}
+		name := strings.ToLower(strings.TrimSpace(d.Username))
+		r := UserSyncResult{Username: d.Username, desired: &d}
+		if name == "" {
+			r.Err = errors.New("The username must input")
+			results = append(results, r)
+			continue
+		}
+		if wanted[name] {
+			r.Err = errors.New("The username " + d.Username + " is repeated")
+			results = append(results, r)
+			continue
+		}
+		wanted[name] = true
+		available, err := rolesOf(d.ContainerID)
+		if err != nil {
+			return nil, err
+		}
+		r.roleIDs, r.Err = resolveRoles(d.Roles, available)
+
+		i, ok := existing[name]
+		if !ok {
+			r.Action = UserSyncCreate
+			results = append(results, r)
+			continue
+		}
+		u := users.Users[i]
+		r.UserID, r.existing = u.ID, &users.Users[i]
+		if u.Container.ID != d.ContainerID {
+			r.Err = errors.New("The user " + d.Username + " is in container " + strconv.Itoa(u.Container.ID) + ", users cannot be moved")
+		}
+		for _, f := range []struct {
+			name      string
+			have, set string
+		}{
+			{"first_name", u.FirstName, d.FirstName},
+			{"last_name", u.LastName, d.LastName},
+			{"email", u.Email, d.Email},
+			{"job_title", u.JobTitle, d.JobTitle},
+			{"telephone", u.Telephone, d.Telephone},
+		} {
+			if f.set != "" && f.have != f.set {
+				r.Changes = append(r.Changes, f.name)
+			}
+		}
+		var current []int
+		for _, role := range u.AccessRoles {
+			current = append(current, role.ID)
+		}
+		if r.Err == nil && len(d.Roles) > 0 && !sameInts(current, r.roleIDs) {
+			r.Changes = append(r.Changes, "roles")
+		}
+		r.Action = UserSyncUnchanged
+		if len(r.Changes) > 0 {
+			r.Action = UserSyncUpdate
+		}
+		results = append(results, r)
+	}
+
+	if options.Delete {
+		keep := make(map[string]bool)
+		for _, k := range options.Keep {
+			keep[strings.ToLower(k)] = true
+		}
+		for _, u := range users.Users {
+			name := strings.ToLower(u.Username)
+			if !wanted[name] && !keep[name] {
+				results = append(results, UserSyncResult{Username: u.Username, Action: UserSyncDelete, UserID: u.ID})
+			}
+		}
+	}
+	if options.DryRun {
+		return results, nil
+	}
+
+	concurrency, interval := options.limits()
+	throttle(len(results), concurrency, interval, func(i int) {
+		r := &results[i]
+		if r.Err != nil || r.Action == UserSyncUnchanged {
+			return
+		}
+		r.Err = c.clone().applyUserSync(r)
+		r.Applied = r.Err == nil
+	})
+	return results, nil
+}
+
+// applyUserSync performs the planned action of r.
+func (c *Client) applyUserSync(r *UserSyncResult) error {
+	d := r.desired
+	switch r.Action {
+	case UserSyncCreate:
+		request := &NewUserRequest{
+			Username:  d.Username,
+			FirstName: d.FirstName,
+			LastName:  d.LastName,
+			Email:     d.Email,
+			JobTitle:  d.JobTitle,
+			Telephone: d.Telephone,
+		}
+		request.Container.ID = d.ContainerID
+		for _, id := range r.roleIDs {
+			request.AccessRoles = append(request.AccessRoles, struct {
+				ID int `json:"id"`
+			}{id})
+		}
+		res, err := c.NewUser(request)
+		if err != nil {
+			return err
+		}
+		if err := res.err(); err != nil {
+			return err
+		}
+		r.UserID = res.ID
+	case UserSyncUpdate:
+		userID := strconv.Itoa(r.UserID)
+		// The API requires the whole user, the desired values are laid over the listed ones.
+		u := r.existing
+		request := &UpdateUserRequest{
+			Username:  u.Username,
+			FirstName: overlay(u.FirstName, d.FirstName),
+			LastName:  overlay(u.LastName, d.LastName),
+			Email:     overlay(u.Email, d.Email),
+			JobTitle:  overlay(u.JobTitle, d.JobTitle),
+			Telephone: overlay(u.Telephone, d.Telephone),
+		}
+		if len(r.Changes) > 1 || !contains(r.Changes, "roles") {
+			if _, err := c.EditUser(userID, request); err != nil {
+				return err
+			}
+		}
+		if contains(r.Changes, "roles") {
+			request := new(UpdateUserRoleRequest)
+			for _, id := range r.roleIDs {
+				request.AccessRoles = append(request.AccessRoles, struct {
+					ID int `json:"id"`
+				}{id})
+			}
+			if _, err := c.EditUserRole(userID, request); err != nil {
+				return err
+			}
+		}
+	case UserSyncDelete:
+		if _, err := c.RemoveUser(strconv.Itoa(r.UserID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overlay returns the desired value, or the current one when nothing is desired.
+func overlay(current, desired string) string {
+	if desired == "" {
+		return current
+	}
+	return desired
+}
+
+// resolveRoles maps role names or IDs to the role IDs available in a container.
+func resolveRoles(names []string, available map[string]int) ([]int, error) {
+	var ids []int
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if id, err := strconv.Atoi(name); err == nil {
+			ids = append(ids, id)
+			continue
+		}
+		id, ok := available[strings.ToLower(name)]
+		if !ok {
+			return nil, errors.New("The role " + name + " is not available in the container")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// sameInts reports whether a and b hold the same values, in any order.
+func sameInts(a, b []int) bool {
+	a, b = uniqueInts(a), uniqueInts(b)
+	if len(a) != len(b) {
+		return false
+	}
+	sort.Ints(a)
+	sort.Ints(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseDesiredUsersJSON exports reads desired users from a JSON array of objects with the keys of DesiredUser.
+func ParseDesiredUsersJSON(r io.Reader) ([]DesiredUser, error) {
+	var users []DesiredUser
+	if err := json.NewDecoder(r).Decode(&users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ParseDesiredUsersCSV exports reads desired users from CSV with a header line naming the columns username, first_name, last_name, email, job_title, telephone, container_id and roles.
+// Several roles are separated by semicolons, as role names may contain spaces.
+func ParseDesiredUsersCSV(r io.Reader) ([]DesiredUser, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, errors.New("The CSV has no username column")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	var users []DesiredUser
+	for _, row := range rows[1:] {
+		u := DesiredUser{
+			Username:  field(row, "username"),
+			FirstName: field(row, "first_name"),
+			LastName:  field(row, "last_name"),
+			Email:     field(row, "email"),
+			JobTitle:  field(row, "job_title"),
+			Telephone: field(row, "telephone"),
+		}
+		if container := field(row, "container_id"); container != "" {
+			id, err := strconv.Atoi(container)
+			if err != nil {
+				return nil, errors.New("The container_id of " + u.Username + " is not a number")
+			}
+			u.ContainerID = id
+		}
+		for _, role := range strings.Split(field(row, "roles"), ";") {
+			if role = strings.TrimSpace(role); role != "" {
+				u.Roles = append(u.Roles, role)
+			}
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
@@ -0,0 +1,179 @@
+package digicert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncUsersSendsChangedFields(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /user", 200, map[string]interface{}{"users": []interface{}{
+		map[string]interface{}{
+			"id": 7, "username": "ada", "first_name": "Ada", "last_name": "Byron", "email": "ada@example.com",
+			"job_title": "CTO", "telephone": "+44 20 7946 0000", "container": map[string]int{"id": 1},
+			"access_roles": []map[string]interface{}{{"id": 3, "name": "User"}},
+		},
+		map[string]interface{}{
+			"id": 8, "username": "grace", "first_name": "Grace", "last_name": "Hopper", "email": "grace@example.com",
+			"telephone": "+1 202 555 0100", "container": map[string]int{"id": 1},
+		},
+	}})
+	api.reply("GET /container/1/role", 200, map[string]interface{}{"access_roles": []map[string]interface{}{{"id": 3, "name": "User"}, {"id": 4, "name": "Manager"}}})
+	api.reply("PUT /user/7", 204, nil)
+	api.reply("PUT /user/8", 204, nil)
+	api.reply("PUT /user/8/role", 204, nil)
+
+	desired := []DesiredUser{
+		// The job title and telephone are not in the export and must be kept.
+		{Username: "Ada", FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"},
+		{Username: "grace", Telephone: "+1 202 555 0199", Roles: []string{"Manager"}},
+	}
+	results, err := c.SyncUsers(desired, &UserSyncOptions{ContainerID: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Err != nil || !r.Applied {
+			t.Errorf("%s: %+v", r.Username, r)
+		}
+	}
+	if want := []string{"last_name"}; !reflect.DeepEqual(results[0].Changes, want) {
+		t.Errorf("Changes of ada = %v, want %v", results[0].Changes, want)
+	}
+	if want := []string{"telephone", "roles"}; !reflect.DeepEqual(results[1].Changes, want) {
+		t.Errorf("Changes of grace = %v, want %v", results[1].Changes, want)
+	}
+
+	// The whole user is sent with the desired values laid over the listed ones.
+	var body map[string]interface{}
+	api.body("PUT /user/7", &body)
+	want := map[string]interface{}{
+		"username": "ada", "first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com",
+		"job_title": "CTO", "telephone": "+44 20 7946 0000",
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("The update of ada sent %v, want %v", body, want)
+	}
+	body = nil
+	api.body("PUT /user/8", &body)
+	want = map[string]interface{}{
+		"username": "grace", "first_name": "Grace", "last_name": "Hopper", "email": "grace@example.com",
+		"job_title": "", "telephone": "+1 202 555 0199",
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("The update of grace sent %v, want %v", body, want)
+	}
+	for _, d := range desired {
+		if d.ContainerID != 0 {
+			t.Errorf("The desired list was modified: %+v", d)
+		}
+	}
+}
+
+func TestSyncUsersCaseChange(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /user", 200, map[string]interface{}{"users": []interface{}{
+		map[string]interface{}{
+			"id": 7, "username": "ada", "first_name": "Ada", "last_name": "smith", "email": "ada@example.com",
+			"container": map[string]int{"id": 1}, "access_roles": []map[string]interface{}{{"id": 3, "name": "User"}},
+		},
+	}})
+	api.reply("GET /container/1/role", 200, map[string]interface{}{"access_roles": []map[string]interface{}{{"id": 3, "name": "User"}, {"id": 4, "name": "Manager"}}})
+	api.reply("PUT /user/7/role", 204, nil)
+
+	results, err := c.SyncUsers([]DesiredUser{{Username: "ada", LastName: "Smith"}}, &UserSyncOptions{ContainerID: "1", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != UserSyncUpdate || !reflect.DeepEqual(results[0].Changes, []string{"last_name"}) {
+		t.Errorf("A case-only change is planned as %+v", results[0])
+	}
+
+	// A role change alone does not update the user details.
+	results, err = c.SyncUsers([]DesiredUser{{Username: "ada", Roles: []string{"manager"}}}, &UserSyncOptions{ContainerID: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.Err != nil || !r.Applied || !reflect.DeepEqual(r.Changes, []string{"roles"}) {
+		t.Errorf("The role change is %+v", r)
+	}
+	if n := api.count("PUT /user/7"); n != 0 {
+		t.Errorf("The user was updated %d times for a role change", n)
+	}
+}
+
+func TestSyncUsersDeleteKeep(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.reply("GET /user", 200, map[string]interface{}{"users": []map[string]interface{}{
+		{"id": 7, "username": "ada", "container": map[string]int{"id": 1}},
+		{"id": 8, "username": "Grace", "container": map[string]int{"id": 1}},
+		{"id": 9, "username": "admin", "container": map[string]int{"id": 1}},
+		{"id": 10, "username": "linus", "container": map[string]int{"id": 1}},
+	}})
+	api.reply("GET /container/1/role", 200, map[string]interface{}{"access_roles": []interface{}{}})
+
+	results, err := c.SyncUsers([]DesiredUser{{Username: "ADA"}}, &UserSyncOptions{ContainerID: "1", DryRun: true, Delete: true, Keep: []string{"ADMIN", "grace"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deleted []int
+	for _, r := range results {
+		if r.Action == UserSyncDelete {
+			deleted = append(deleted, r.UserID)
+		}
+	}
+	if !reflect.DeepEqual(deleted, []int{10}) {
+		t.Errorf("The deletions planned are %v, want [10]", deleted)
+	}
+
+	// Without Delete, nobody is deleted.
+	results, err = c.SyncUsers([]DesiredUser{{Username: "ada"}}, &UserSyncOptions{ContainerID: "1", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Action == UserSyncDelete {
+			t.Errorf("%s is deleted without Delete", r.Username)
+		}
+	}
+}
+
+func TestParseDesiredUsersCSV(t *testing.T) {
+	users, err := ParseDesiredUsersCSV(strings.NewReader("Username, email ,container_id,roles\n" +
+		"ada,ada@example.com,2,Administrator; Limited User ;\n" +
+		"grace,grace@example.com,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DesiredUser{
+		{Username: "ada", Email: "ada@example.com", ContainerID: 2, Roles: []string{"Administrator", "Limited User"}},
+		{Username: "grace", Email: "grace@example.com"},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("ParseDesiredUsersCSV = %+v, want %+v", users, want)
+	}
+
+	if _, err := ParseDesiredUsersCSV(strings.NewReader("username,container_id\nada,emea\n")); err == nil || !strings.Contains(err.Error(), "container_id of ada") {
+		t.Errorf("ParseDesiredUsersCSV error = %v, want a container_id error", err)
+	}
+	if _, err := ParseDesiredUsersCSV(strings.NewReader("user,email\nada,ada@example.com\n")); err == nil || !strings.Contains(err.Error(), "no username column") {
+		t.Errorf("ParseDesiredUsersCSV error = %v, want a missing username column", err)
+	}
+	if users, err := ParseDesiredUsersCSV(strings.NewReader("")); users != nil || err != nil {
+		t.Errorf("ParseDesiredUsersCSV of nothing = %v, %v", users, err)
+	}
+}
+
+func TestParseDesiredUsersJSON(t *testing.T) {
+	users, err := ParseDesiredUsersJSON(strings.NewReader(`[{"username":"ada","container_id":2,"roles":["Administrator","3"]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []DesiredUser{{Username: "ada", ContainerID: 2, Roles: []string{"Administrator", "3"}}}; !reflect.DeepEqual(users, want) {
+		t.Errorf("ParseDesiredUsersJSON = %+v, want %+v", users, want)
+	}
+	if _, err := ParseDesiredUsersJSON(strings.NewReader(`[{"username":"ada","container_id":"emea"}]`)); err == nil {
+		t.Error("ParseDesiredUsersJSON accepted a non-numeric container_id")
+	}
+}
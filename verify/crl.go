@@ -0,0 +1,56 @@
+package verify
+
+import (
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// checkCRL downloads the CRLs of the CDP extension until one can be verified against issuer and looks the leaf up in it.
+func checkCRL(client *http.Client, leaf, issuer *x509.Certificate, now time.Time) CRLResult {
+	r := CRLResult{Checked: true}
+	for _, url := range leaf.CRLDistributionPoints {
+		r.URL = url
+		crl, err := fetchCRL(client, url)
+		if err != nil {
+			r.Err = err
+			continue
+		}
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			r.Err = err
+			continue
+		}
+		r.Err = nil
+		r.NextUpdate = crl.NextUpdate
+		if !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate) {
+			r.Err = errors.New("The CRL is stale, its next update is in the past")
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				r.Revoked = true
+				r.RevokedAt = entry.RevocationTime
+				break
+			}
+		}
+		return r
+	}
+	return r
+}
+
+func fetchCRL(client *http.Client, url string) (*x509.RevocationList, error) {
+	res, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("The CRL distribution point returned " + res.Status)
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseRevocationList(data)
+}
@@ -0,0 +1,219 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// The OCSP structures of RFC 6960, limited to what a status query needs.
+
+var (
+	oidSHA1             = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidOCSPBasic        = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+	signatureAlgorithms = map[string]x509.SignatureAlgorithm{
+		"1.2.840.113549.1.1.5":  x509.SHA1WithRSA,
+		"1.2.840.113549.1.1.11": x509.SHA256WithRSA,
+		"1.2.840.113549.1.1.12": x509.SHA384WithRSA,
+		"1.2.840.113549.1.1.13": x509.SHA512WithRSA,
+		"1.2.840.10045.4.1":     x509.ECDSAWithSHA1,
+		"1.2.840.10045.4.3.2":   x509.ECDSAWithSHA256,
+		"1.2.840.10045.4.3.3":   x509.ECDSAWithSHA384,
+		"1.2.840.10045.4.3.4":   x509.ECDSAWithSHA512,
+		"1.3.101.112":           x509.PureEd25519,
+	}
+)
+
+type certID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type ocspRequest struct {
+	TBSRequest struct {
+		Version     int `asn1:"explicit,tag:0,default:0,optional"`
+		RequestList []struct {
+			Cert certID
+		}
+	}
+}
+
+type ocspResponse struct {
+	Status   asn1.Enumerated
+	Response struct {
+		ResponseType asn1.ObjectIdentifier
+		Response     []byte
+	} `asn1:"explicit,tag:0,optional"`
+}
+
+type basicResponse struct {
+	TBSResponseData    responseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type responseData struct {
+	Raw                asn1.RawContent
+	Version            int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID     asn1.RawValue
+	ProducedAt         time.Time `asn1:"generalized"`
+	Responses          []singleResponse
+	ResponseExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type singleResponse struct {
+	CertID  certID
+	Good    asn1.Flag `asn1:"tag:0,optional"`
+	Revoked struct {
+		RevocationTime time.Time       `asn1:"generalized"`
+		Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+	} `asn1:"tag:1,optional"`
+	Unknown          asn1.Flag        `asn1:"tag:2,optional"`
+	ThisUpdate       time.Time        `asn1:"generalized"`
+	NextUpdate       time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+	SingleExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+// newCertID identifies leaf to the responder by the SHA-1 hashes of its issuer name and key.
+func newCertID(leaf, issuer *x509.Certificate) (certID, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return certID{}, err
+	}
+	nameHash := sha1.Sum(issuer.RawSubject)
+	keyHash := sha1.Sum(spki.PublicKey.RightAlign())
+	return certID{
+		HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA1, Parameters: asn1.NullRawValue},
+		NameHash:      nameHash[:],
+		IssuerKeyHash: keyHash[:],
+		SerialNumber:  leaf.SerialNumber,
+	}, nil
+}
+
+// checkOCSP posts a status request to the first responder of the AIA extension and verifies the signed answer.
+func checkOCSP(client *http.Client, leaf, issuer *x509.Certificate, now time.Time) OCSPResult {
+	r := OCSPResult{Checked: true, Responder: leaf.OCSPServer[0]}
+	id, err := newCertID(leaf, issuer)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	var req ocspRequest
+	req.TBSRequest.RequestList = append(req.TBSRequest.RequestList, struct{ Cert certID }{id})
+	body, err := asn1.Marshal(req)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	res, err := client.Post(r.Responder, "application/ocsp-request", bytes.NewReader(body))
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		r.Err = errors.New("The OCSP responder returned " + res.Status)
+		return r
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	single, err := parseOCSPResponse(data, id, issuer, now)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.ThisUpdate, r.NextUpdate = single.ThisUpdate, single.NextUpdate
+	if !single.NextUpdate.IsZero() && now.After(single.NextUpdate) {
+		r.Err = errors.New("The OCSP response is stale, its next update is in the past")
+	}
+	switch {
+	case bool(single.Good):
+		r.Status = StatusGood
+	case bool(single.Unknown):
+		r.Status = StatusUnknown
+	default:
+		r.Status = StatusRevoked
+		r.RevokedAt = single.Revoked.RevocationTime
+	}
+	return r
+}
+
+// parseOCSPResponse returns the status of id once the response is proven to be signed by issuer or by a responder it delegated, valid at now.
+func parseOCSPResponse(data []byte, id certID, issuer *x509.Certificate, now time.Time) (*singleResponse, error) {
+	var resp ocspResponse
+	if _, err := asn1.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != 0 {
+		return nil, errors.New("The OCSP responder returned an error status")
+	}
+	if !resp.Response.ResponseType.Equal(oidOCSPBasic) {
+		return nil, errors.New("The OCSP response type is not supported")
+	}
+	var basic basicResponse
+	if _, err := asn1.Unmarshal(resp.Response.Response, &basic); err != nil {
+		return nil, err
+	}
+
+	signer := issuer
+	if len(basic.Certificates) > 0 {
+		responder, err := x509.ParseCertificate(basic.Certificates[0].FullBytes)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(responder.Raw, issuer.Raw) {
+			if err := responder.CheckSignatureFrom(issuer); err != nil {
+				return nil, err
+			}
+			if !hasOCSPSigning(responder) {
+				return nil, errors.New("The OCSP responder certificate is not authorized for OCSP signing")
+			}
+			if now.Before(responder.NotBefore) || now.After(responder.NotAfter) {
+				return nil, errors.New("The OCSP responder certificate is not valid at " + now.UTC().Format(time.RFC3339))
+			}
+		}
+		signer = responder
+	}
+	algo, ok := signatureAlgorithms[basic.SignatureAlgorithm.Algorithm.String()]
+	if !ok {
+		return nil, errors.New("The OCSP signature algorithm is not supported")
+	}
+	if err := signer.CheckSignature(algo, basic.TBSResponseData.Raw, basic.Signature.RightAlign()); err != nil {
+		return nil, err
+	}
+
+	for i := range basic.TBSResponseData.Responses {
+		single := &basic.TBSResponseData.Responses[i]
+		if single.CertID.SerialNumber.Cmp(id.SerialNumber) == 0 &&
+			bytes.Equal(single.CertID.NameHash, id.NameHash) &&
+			bytes.Equal(single.CertID.IssuerKeyHash, id.IssuerKeyHash) {
+			return single, nil
+		}
+	}
+	return nil, errors.New("The OCSP response does not cover the certificate")
+}
+
+func hasOCSPSigning(cert *x509.Certificate) bool {
+	for _, usage := range cert.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageOCSPSigning {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package verify
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"strconv"
+)
+
+// oidSCTList is the X.509 extension carrying the embedded SCTs, RFC 6962 section 3.3.
+var oidSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// checkSCTs counts the SCTs embedded in leaf, they are only required when CT was not disabled on the order.
+func checkSCTs(leaf *x509.Certificate, options *Options) CTResult {
+	r := CTResult{Required: !options.DisableCt}
+	min := options.MinSCTs
+	if min == 0 {
+		min = 2
+	}
+	for _, ext := range leaf.Extensions {
+		if !ext.Id.Equal(oidSCTList) {
+			continue
+		}
+		var list []byte
+		if _, err := asn1.Unmarshal(ext.Value, &list); err != nil {
+			r.Err = err
+			return r
+		}
+		r.SCTs, r.Err = countSCTs(list)
+		if r.Err != nil {
+			return r
+		}
+	}
+	if r.Required && r.SCTs < min {
+		r.Err = errors.New("The certificate embeds " + strconv.Itoa(r.SCTs) + " SCTs, " + strconv.Itoa(min) + " are required")
+	}
+	return r
+}
+
+// countSCTs walks a TLS encoded SignedCertificateTimestampList, a 2 bytes length followed by 2 bytes length prefixed SCTs.
+func countSCTs(list []byte) (int, error) {
+	if len(list) < 2 || int(list[0])<<8|int(list[1]) != len(list)-2 {
+		return 0, errors.New("The SCT list is malformed")
+	}
+	n := 0
+	for rest := list[2:]; len(rest) > 0; n++ {
+		if len(rest) < 2 {
+			return 0, errors.New("The SCT list is malformed")
+		}
+		size := int(rest[0])<<8 | int(rest[1])
+		if size == 0 || len(rest) < 2+size {
+			return 0, errors.New("The SCT list is malformed")
+		}
+		rest = rest[2+size:]
+	}
+	return n, nil
+}
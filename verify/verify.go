@@ -0,0 +1,129 @@
+// Package verify checks that a certificate issued through the DigiCert API is usable: not revoked according to OCSP and CRL, and logged to Certificate Transparency.
+package verify
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Status presents a revocation status.
+type Status string
+
+// Revocation statuses as reported by OCSP.
+const (
+	StatusGood    Status = "good"
+	StatusRevoked Status = "revoked"
+	StatusUnknown Status = "unknown"
+)
+
+// Options presents what Check verifies and how.
+type Options struct {
+	// HTTPClient fetches OCSP responses and CRLs, defaults to a client with a 10 seconds timeout.
+	HTTPClient *http.Client
+	// DisableCt mirrors the disable_ct flag of the order, no SCT is expected when set.
+	DisableCt bool
+	// MinSCTs is the number of embedded SCTs required, defaults to 2.
+	MinSCTs  int
+	SkipOCSP bool
+	SkipCRL  bool
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// OCSPResult presents the answer of the OCSP responder found in the AIA extension.
+type OCSPResult struct {
+	Checked    bool
+	Responder  string
+	Status     Status
+	RevokedAt  time.Time
+	ThisUpdate time.Time
+	NextUpdate time.Time
+	Err        error
+}
+
+// CRLResult presents the membership of the certificate in the CRLs of the CDP extension.
+type CRLResult struct {
+	Checked    bool
+	URL        string
+	Revoked    bool
+	RevokedAt  time.Time
+	NextUpdate time.Time
+	Err        error
+}
+
+// CTResult presents the SCTs embedded in the certificate.
+type CTResult struct {
+	Required bool
+	SCTs     int
+	Err      error
+}
+
+// Report presents the health of an issued certificate.
+type Report struct {
+	Subject      string
+	SerialNumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Expired      bool
+	// NotYetValid is set when the certificate is used before its NotBefore.
+	NotYetValid bool
+	// ChainErr is set when the leaf is not signed by the next certificate of the chain.
+	ChainErr error
+	OCSP     OCSPResult
+	CRL      CRLResult
+	CT       CTResult
+}
+
+// Healthy reports whether the certificate is within its validity period and no check failed or found it revoked.
+func (r *Report) Healthy() bool {
+	if r.Expired || r.NotYetValid || r.ChainErr != nil {
+		return false
+	}
+	if r.OCSP.Checked && (r.OCSP.Err != nil || r.OCSP.Status != StatusGood) {
+		return false
+	}
+	if r.CRL.Checked && (r.CRL.Err != nil || r.CRL.Revoked) {
+		return false
+	}
+	return r.CT.Err == nil
+}
+
+// Check verifies the leaf of chain, chain is leaf first followed by its issuer as returned by digicert.ParseCertificateChain.
+// Network and parsing failures are recorded in the report, an error is only returned when the chain cannot be checked at all.
+func Check(chain []*x509.Certificate, options *Options) (*Report, error) {
+	if len(chain) < 2 {
+		return nil, errors.New("The chain must contain the certificate and its issuer")
+	}
+	if options == nil {
+		options = new(Options)
+	}
+	client := options.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	now := time.Now
+	if options.Now != nil {
+		now = options.Now
+	}
+
+	leaf, issuer := chain[0], chain[1]
+	r := &Report{
+		Subject:      leaf.Subject.String(),
+		SerialNumber: leaf.SerialNumber.Text(16),
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+		Expired:      now().After(leaf.NotAfter),
+		NotYetValid:  now().Before(leaf.NotBefore),
+		ChainErr:     leaf.CheckSignatureFrom(issuer),
+	}
+	if !options.SkipOCSP && len(leaf.OCSPServer) > 0 {
+		r.OCSP = checkOCSP(client, leaf, issuer, now())
+	}
+	if !options.SkipCRL && len(leaf.CRLDistributionPoints) > 0 {
+		r.CRL = checkCRL(client, leaf, issuer, now())
+	}
+	r.CT = checkSCTs(leaf, options)
+	return r, nil
+}
@@ -0,0 +1,431 @@
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+
+// testPKI is a local CA with an OCSP responder and a CRL distribution point standing in for DigiCert.
+type testPKI struct {
+	t      *testing.T
+	server *httptest.Server
+	now    time.Time
+
+	ca    *x509.Certificate
+	caKey *ecdsa.PrivateKey
+	leaf  *x509.Certificate
+
+	// revoked is the revocation state served by both the responder and the CRL.
+	revoked bool
+	// nextUpdate of the OCSP response and of the CRL.
+	nextUpdate time.Time
+	// responder and responderKey sign the OCSP responses instead of the CA when set.
+	responder    *x509.Certificate
+	responderKey *ecdsa.PrivateKey
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	p := &testPKI{t: t, now: time.Now().UTC().Truncate(time.Second)}
+	p.nextUpdate = p.now.Add(24 * time.Hour)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ocsp", p.serveOCSP)
+	mux.HandleFunc("/crl", p.serveCRL)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	p.ca, p.caKey = p.certificate(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, nil, nil)
+	p.leaf, _ = p.certificate(&x509.Certificate{
+		SerialNumber:          big.NewInt(0x1234),
+		Subject:               pkix.Name{CommonName: "www.example.com"},
+		DNSNames:              []string{"www.example.com"},
+		OCSPServer:            []string{p.server.URL + "/ocsp"},
+		CRLDistributionPoints: []string{p.server.URL + "/crl"},
+	}, p.ca, p.caKey)
+	return p
+}
+
+// certificate issues template from parent, or self-signs it when parent is nil. Without a NotAfter, the template is valid from an hour ago for 90 days.
+func (p *testPKI) certificate(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if template.NotAfter.IsZero() {
+		template.NotBefore, template.NotAfter = p.now.Add(-time.Hour), p.now.Add(90*24*time.Hour)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return cert, key
+}
+
+func (p *testPKI) check(options *Options) *Report {
+	if options == nil {
+		options = new(Options)
+	}
+	options.HTTPClient = p.server.Client()
+	options.DisableCt = true
+	if options.Now == nil {
+		options.Now = func() time.Time { return p.now }
+	}
+	report, err := Check([]*x509.Certificate{p.leaf, p.ca}, options)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return report
+}
+
+// serveOCSP answers the request with the encoding of RFC 6960, built from the same structures the client parses.
+func (p *testPKI) serveOCSP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.Header.Get("Content-Type") != "application/ocsp-request" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	var req ocspRequest
+	if _, err := asn1.Unmarshal(body, &req); err != nil || len(req.TBSRequest.RequestList) != 1 {
+		http.Error(w, "malformed request", http.StatusBadRequest)
+		return
+	}
+	id := req.TBSRequest.RequestList[0].Cert
+	want, _ := newCertID(p.leaf, p.ca)
+	if id.SerialNumber.Cmp(want.SerialNumber) != 0 || string(id.NameHash) != string(want.NameHash) || string(id.IssuerKeyHash) != string(want.IssuerKeyHash) {
+		p.t.Errorf("The OCSP request identifies %+v, want %+v", id, want)
+	}
+
+	single := singleResponse{CertID: id, ThisUpdate: p.now.Add(-time.Hour), NextUpdate: p.nextUpdate}
+	if p.revoked {
+		single.Revoked.RevocationTime = p.now.Add(-30 * time.Minute)
+	} else {
+		single.Good = true
+	}
+	signer, signerKey := p.ca, p.caKey
+	if p.responder != nil {
+		signer, signerKey = p.responder, p.responderKey
+	}
+	keyHash := sha1.Sum(signer.RawSubjectPublicKeyInfo)
+	byKey, _ := asn1.Marshal(keyHash[:])
+	data := responseData{
+		RawResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: byKey},
+		ProducedAt:     p.now,
+		Responses:      []singleResponse{single},
+	}
+	tbs, err := asn1.Marshal(data)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	data.Raw = tbs
+	digest := sha256.Sum256(tbs)
+	signature, err := signerKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	basic := basicResponse{
+		TBSResponseData:    data,
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256},
+		Signature:          asn1.BitString{Bytes: signature, BitLength: 8 * len(signature)},
+	}
+	if p.responder != nil {
+		basic.Certificates = []asn1.RawValue{{FullBytes: p.responder.Raw}}
+	}
+	var resp ocspResponse
+	resp.Response.ResponseType = oidOCSPBasic
+	if resp.Response.Response, err = asn1.Marshal(basic); err != nil {
+		p.t.Fatal(err)
+	}
+	out, err := asn1.Marshal(resp)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	w.Write(out)
+}
+
+func (p *testPKI) serveCRL(w http.ResponseWriter, r *http.Request) {
+	list := &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: p.now.Add(-time.Hour),
+		NextUpdate: p.nextUpdate,
+	}
+	if p.revoked {
+		list.RevokedCertificateEntries = []x509.RevocationListEntry{{
+			SerialNumber:   p.leaf.SerialNumber,
+			RevocationTime: p.now.Add(-30 * time.Minute),
+		}}
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, list, p.ca, p.caKey)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	w.Write(der)
+}
+
+func TestCheckGood(t *testing.T) {
+	p := newTestPKI(t)
+	r := p.check(nil)
+	if r.OCSP.Err != nil || r.OCSP.Status != StatusGood {
+		t.Fatalf("OCSP = %+v, want good", r.OCSP)
+	}
+	if !r.OCSP.NextUpdate.Equal(p.nextUpdate) {
+		t.Errorf("OCSP next update = %v, want %v", r.OCSP.NextUpdate, p.nextUpdate)
+	}
+	if r.CRL.Err != nil || r.CRL.Revoked {
+		t.Fatalf("CRL = %+v, want not revoked", r.CRL)
+	}
+	if !r.Healthy() {
+		t.Errorf("The report is not healthy: %+v", r)
+	}
+}
+
+func TestCheckRevoked(t *testing.T) {
+	p := newTestPKI(t)
+	p.revoked = true
+	r := p.check(nil)
+	if r.OCSP.Err != nil || r.OCSP.Status != StatusRevoked {
+		t.Fatalf("OCSP = %+v, want revoked", r.OCSP)
+	}
+	if want := p.now.Add(-30 * time.Minute); !r.OCSP.RevokedAt.Equal(want) {
+		t.Errorf("OCSP revoked at %v, want %v", r.OCSP.RevokedAt, want)
+	}
+	if r.CRL.Err != nil || !r.CRL.Revoked {
+		t.Fatalf("CRL = %+v, want revoked", r.CRL)
+	}
+	if r.Healthy() {
+		t.Error("A revoked certificate is reported healthy")
+	}
+}
+
+func TestCheckStale(t *testing.T) {
+	p := newTestPKI(t)
+	later := p.nextUpdate.Add(time.Minute)
+	r := p.check(&Options{Now: func() time.Time { return later }})
+	if r.OCSP.Err == nil || !strings.Contains(r.OCSP.Err.Error(), "stale") {
+		t.Errorf("OCSP error = %v, want stale", r.OCSP.Err)
+	}
+	if r.CRL.Err == nil || !strings.Contains(r.CRL.Err.Error(), "stale") {
+		t.Errorf("CRL error = %v, want stale", r.CRL.Err)
+	}
+	if r.Healthy() {
+		t.Error("A certificate with stale revocation data is reported healthy")
+	}
+}
+
+func TestCheckDelegatedResponder(t *testing.T) {
+	p := newTestPKI(t)
+	p.responder, p.responderKey = p.certificate(&x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Test OCSP Responder"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+	}, p.ca, p.caKey)
+	if r := p.check(nil); r.OCSP.Err != nil || r.OCSP.Status != StatusGood {
+		t.Fatalf("OCSP = %+v, want good", r.OCSP)
+	}
+
+	// A certificate of the CA without the OCSP signing usage may not answer for it.
+	p.responder, p.responderKey = p.certificate(&x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "Test Server"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, p.ca, p.caKey)
+	if r := p.check(nil); r.OCSP.Err == nil {
+		t.Fatalf("OCSP = %+v, want an unauthorized responder error", r.OCSP)
+	}
+
+	// A responder certificate outside its validity period may not answer.
+	for name, validity := range map[string][2]time.Time{
+		"expired":       {p.now.Add(-48 * time.Hour), p.now.Add(-time.Hour)},
+		"not yet valid": {p.now.Add(time.Hour), p.now.Add(48 * time.Hour)},
+	} {
+		p.responder, p.responderKey = p.certificate(&x509.Certificate{
+			SerialNumber: big.NewInt(4),
+			Subject:      pkix.Name{CommonName: "Test OCSP Responder"},
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+			NotBefore:    validity[0],
+			NotAfter:     validity[1],
+		}, p.ca, p.caKey)
+		r := p.check(nil)
+		if r.OCSP.Err == nil || !strings.Contains(r.OCSP.Err.Error(), "not valid") {
+			t.Errorf("OCSP with a responder %s = %+v, want a validity error", name, r.OCSP)
+		}
+		if r.Healthy() {
+			t.Errorf("The report is healthy with a responder %s", name)
+		}
+	}
+}
+
+func TestCheckNotYetValid(t *testing.T) {
+	p := newTestPKI(t)
+	r := p.check(&Options{Now: func() time.Time { return p.now.Add(-2 * time.Hour) }})
+	if !r.NotYetValid || r.Expired {
+		t.Fatalf("NotYetValid = %v, Expired = %v, want a certificate not yet valid", r.NotYetValid, r.Expired)
+	}
+	if r.Healthy() {
+		t.Error("A certificate used before its NotBefore is reported healthy")
+	}
+	if r := p.check(nil); r.NotYetValid || !r.Healthy() {
+		t.Errorf("NotYetValid = %v within the validity period", r.NotYetValid)
+	}
+}
+
+func TestCheckForgedResponse(t *testing.T) {
+	p := newTestPKI(t)
+	other := newTestPKI(t)
+	// The responder certificate is issued by another CA.
+	p.responder, p.responderKey = other.certificate(&x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Forged OCSP Responder"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+	}, other.ca, other.caKey)
+	if r := p.check(nil); r.OCSP.Err == nil {
+		t.Fatalf("OCSP = %+v, want a signature error", r.OCSP)
+	}
+}
+
+func TestCountSCTs(t *testing.T) {
+	tests := []struct {
+		list []byte
+		n    int
+		ok   bool
+	}{
+		{[]byte{0, 0}, 0, true},
+		{[]byte{0, 3, 0, 1, 0xaa}, 1, true},
+		{[]byte{0, 7, 0, 1, 0xaa, 0, 2, 0xbb, 0xcc}, 2, true},
+		{[]byte{0, 4, 0, 1, 0xaa}, 0, false},
+		{[]byte{0, 2, 0, 0}, 0, false},
+		{[]byte{0, 3, 0, 5, 0xaa}, 0, false},
+	}
+	for _, tt := range tests {
+		n, err := countSCTs(tt.list)
+		if (err == nil) != tt.ok || n != tt.n {
+			t.Errorf("countSCTs(%x) = %d, %v, want %d, ok %v", tt.list, n, err, tt.n, tt.ok)
+		}
+	}
+}
+
+// realOCSPResponse is a response of the GTS CA 1C3 responder for serial f374542e3c7a68360a00000001103462,
+// kept verbatim so the parser is checked against an encoding it did not produce.
+const realOCSPResponse = "308201d40a0100a08201cd308201c906092b0601050507300101048201ba308201b63081" +
+	"9fa21604148a747faf85cdee95cd3d9cd0e24614f371351d27180f323032313131303731" +
+	"34323535335a30743072304a300906052b0e03021a05000414c72e798addff6134b3baed" +
+	"4742b8bbc6c024076304148a747faf85cdee95cd3d9cd0e24614f371351d27021100f374" +
+	"542e3c7a68360a000000011034628000180f32303231313130373134323535315aa01118" +
+	"0f32303231313131343133323535305a300d06092a864886f70d01010b05000382010100" +
+	"87749296e681abe36f2efef047730178ce57e948426959ac62ac5f25b9a63ba3b7f31b9f" +
+	"683aea384d21845c8dda09498f2531c78f3add3969ca4092f31f58ac3c2613719d63b7b9" +
+	"a5260e52814c827f8dd44f4f753b2528bcd03ccec02cdcd4918247f5323f8cfc12cee4ac" +
+	"8f0361587b267019cfd12336db09b04eac59807a480213cfcd9913a3aa2d13a6c88c0a75" +
+	"0475a0e991806d94ec0fc9dab599171a43a08e6d935b4a4a13dff9c4a97ad46cef6fb4d6" +
+	"1cb2363d788c12d81cce851b478889c2e05d80cd00ae346772a1e7502f011e2ed9be8ef4" +
+	"b194c8b65d6e33671d878cfb30267972075b062ff3d56b51984bf685161afc6e2538dd6e" +
+	"6a23063c"
+
+// realOCSPIssuer is the GTS CA 1C3 certificate that signed realOCSPResponse.
+const realOCSPIssuer = `-----BEGIN CERTIFICATE-----
+MIIFljCCA36gAwIBAgINAgO8U1lrNMcY9QFQZjANBgkqhkiG9w0BAQsFADBHMQsw
+CQYDVQQGEwJVUzEiMCAGA1UEChMZR29vZ2xlIFRydXN0IFNlcnZpY2VzIExMQzEU
+MBIGA1UEAxMLR1RTIFJvb3QgUjEwHhcNMjAwODEzMDAwMDQyWhcNMjcwOTMwMDAw
+MDQyWjBGMQswCQYDVQQGEwJVUzEiMCAGA1UEChMZR29vZ2xlIFRydXN0IFNlcnZp
+Y2VzIExMQzETMBEGA1UEAxMKR1RTIENBIDFDMzCCASIwDQYJKoZIhvcNAQEBBQAD
+ggEPADCCAQoCggEBAPWI3+dijB43+DdCkH9sh9D7ZYIl/ejLa6T/belaI+KZ9hzp
+kgOZE3wJCor6QtZeViSqejOEH9Hpabu5dOxXTGZok3c3VVP+ORBNtzS7XyV3NzsX
+lOo85Z3VvMO0Q+sup0fvsEQRY9i0QYXdQTBIkxu/t/bgRQIh4JZCF8/ZK2VWNAcm
+BA2o/X3KLu/qSHw3TT8An4Pf73WELnlXXPxXbhqW//yMmqaZviXZf5YsBvcRKgKA
+gOtjGDxQSYflispfGStZloEAoPtR28p3CwvJlk/vcEnHXG0g/Zm0tOLKLnf9LdwL
+tmsTDIwZKxeWmLnwi/agJ7u2441Rj72ux5uxiZ0CAwEAAaOCAYAwggF8MA4GA1Ud
+DwEB/wQEAwIBhjAdBgNVHSUEFjAUBggrBgEFBQcDAQYIKwYBBQUHAwIwEgYDVR0T
+AQH/BAgwBgEB/wIBADAdBgNVHQ4EFgQUinR/r4XN7pXNPZzQ4kYU83E1HScwHwYD
+VR0jBBgwFoAU5K8rJnEaK0gnhS9SZizv8IkTcT4waAYIKwYBBQUHAQEEXDBaMCYG
+CCsGAQUFBzABhhpodHRwOi8vb2NzcC5wa2kuZ29vZy9ndHNyMTAwBggrBgEFBQcw
+AoYkaHR0cDovL3BraS5nb29nL3JlcG8vY2VydHMvZ3RzcjEuZGVyMDQGA1UdHwQt
+MCswKaAnoCWGI2h0dHA6Ly9jcmwucGtpLmdvb2cvZ3RzcjEvZ3RzcjEuY3JsMFcG
+A1UdIARQME4wOAYKKwYBBAHWeQIFAzAqMCgGCCsGAQUFBwIBFhxodHRwczovL3Br
+aS5nb29nL3JlcG9zaXRvcnkvMAgGBmeBDAECATAIBgZngQwBAgIwDQYJKoZIhvcN
+AQELBQADggIBAIl9rCBcDDy+mqhXlRu0rvqrpXJxtDaV/d9AEQNMwkYUuxQkq/BQ
+cSLbrcRuf8/xam/IgxvYzolfh2yHuKkMo5uhYpSTld9brmYZCwKWnvy15xBpPnrL
+RklfRuFBsdeYTWU0AIAaP0+fbH9JAIFTQaSSIYKCGvGjRFsqUBITTcFTNvNCCK9U
++o53UxtkOCcXCb1YyRt8OS1b887U7ZfbFAO/CVMkH8IMBHmYJvJh8VNS/UKMG2Yr
+PxWhu//2m+OBmgEGcYk1KCTd4b3rGS3hSMs9WYNRtHTGnXzGsYZbr8w0xNPM1IER
+lQCh9BIiAfq0g3GvjLeMcySsN1PCAJA/Ef5c7TaUEDu9Ka7ixzpiO2xj2YC/WXGs
+Yye5TBeg2vZzFb8q3o/zpWwygTMD0IZRcZk0upONXbVRWPeyk+gB9lm+cZv9TSjO
+z23HFtz30dZGm6fKa+l3D/2gthsjgx0QGtkJAITgRNOidSOzNIb2ILCkXhAd4FJG
+AJ2xDx8hcFH1mt0G/FX0Kw4zd8NLQsLxdxP8c4CU6x+7Nz/OAipmsHMdMqUybDKw
+juDEI/9bfU1lcKwrmz3O2+BtjjKAvpafkmO8l7tdufThcV4q5O8DIrGKZTqPwJNl
+1IXNDw9bg1kWRxYtnCQ6yICmJhSFm/Y3m6xv+cXDBlHz4n/FsRC6UfTd
+-----END CERTIFICATE-----`
+
+func TestParseRealOCSPResponse(t *testing.T) {
+	block, _ := pem.Decode([]byte(realOCSPIssuer))
+	issuer, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := hex.DecodeString(realOCSPResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := new(big.Int).SetString("f374542e3c7a68360a00000001103462", 16)
+	id, err := newCertID(&x509.Certificate{SerialNumber: serial}, issuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2021, 11, 8, 0, 0, 0, 0, time.UTC)
+	single, err := parseOCSPResponse(data, id, issuer, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !single.Good {
+		t.Errorf("The response status is not good: %+v", single)
+	}
+	if want := time.Date(2021, 11, 7, 14, 25, 51, 0, time.UTC); !single.ThisUpdate.Equal(want) {
+		t.Errorf("this update = %v, want %v", single.ThisUpdate, want)
+	}
+	if want := time.Date(2021, 11, 14, 13, 25, 50, 0, time.UTC); !single.NextUpdate.Equal(want) {
+		t.Errorf("next update = %v, want %v", single.NextUpdate, want)
+	}
+
+	// Every field of the CertID must match, not only the serial and the issuer key.
+	for name, alter := range map[string]func(*certID){
+		"serial": func(id *certID) { id.SerialNumber = big.NewInt(1) },
+		"name":   func(id *certID) { id.NameHash = make([]byte, len(id.NameHash)) },
+		"key":    func(id *certID) { id.IssuerKeyHash = make([]byte, len(id.IssuerKeyHash)) },
+	} {
+		other := id
+		alter(&other)
+		if _, err := parseOCSPResponse(data, other, issuer, at); err == nil {
+			t.Errorf("A CertID with another %s is covered by the response", name)
+		}
+	}
+}